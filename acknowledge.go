@@ -0,0 +1,127 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ACKNOWLEDGE(0x05)异常的重发策略
+type AcknowledgePolicy struct {
+	// 两次发送之间的间隔,须大于0,避免刚应答ACKNOWLEDGE的设备被连续的请求淹没
+	Delay time.Duration
+	// 自首次发送起的最长等待时间,须不小于Delay
+	MaxWait time.Duration
+	// 是否重新发送写请求(0x05/0x06/0x0F/0x10)与诊断请求(0x08),默认不重发
+	// 设备可能已执行了应答ACKNOWLEDGE的写请求,重发非幂等的写入(如累加、触发动作)会被重复执行,
+	// 仅在确认写入幂等时开启
+	RetryWrites bool
+	// 等待超过MaxWait放弃请求时调用,可选,用于告警等;err为返回给调用方的*RetryExhaustedError
+	// 在发起请求的goroutine中同步调用,调用时不持有任何锁;没有地址的请求address为0
+	OnGiveUp func(code byte, address uint16, err error)
+}
+
+type acknowledgeSlaver struct {
+	Slaver
+	policy AcknowledgePolicy
+}
+
+// RetryOnAcknowledge 包装Slaver,将ACKNOWLEDGE(0x05)异常视为请求已接受、仍在处理中(常见于编程命令),
+// 每隔policy.Delay重新发送同一请求,直到得到其他响应或错误
+// 写请求与诊断请求默认不重发,直接返回ACKNOWLEDGE异常,除非设置了policy.RetryWrites
+// 自首次发送起等待超过policy.MaxWait时调用policy.OnGiveUp并返回*RetryExhaustedError,其Last为最后一次的ACKNOWLEDGE异常
+// 等待期间上下文结束时返回ctx.Err();Delay不大于0或MaxWait小于Delay时返回ErrOutOfRange
+func RetryOnAcknowledge(s Slaver, policy AcknowledgePolicy) (Slaver, error) {
+	if policy.Delay <= 0 {
+		return nil, fmt.Errorf("%w: acknowledge delay '%v' must be positive", ErrOutOfRange, policy.Delay)
+	}
+	if policy.MaxWait < policy.Delay {
+		return nil, fmt.Errorf("%w: acknowledge max wait '%v' is shorter than delay '%v'", ErrOutOfRange, policy.MaxWait, policy.Delay)
+	}
+	return &acknowledgeSlaver{Slaver: s, policy: policy}, nil
+}
+
+// retries 功能码code的请求是否可以重新发送
+func (p AcknowledgePolicy) retries(code byte) bool {
+	switch code {
+	case WRITE_SINGLE_COIL, WRITE_SINGLE_REGISTER, WRITE_MULTIPLE_COILS, WRITE_MULTIPLE_REGISTERS, DIAGNOSTICS:
+		return p.RetryWrites
+	}
+	return true
+}
+
+// do 发送功能码code、起始地址address的请求,收到ACKNOWLEDGE异常时延时后重新发送
+func (s *acknowledgeSlaver) do(ctx context.Context, code byte, address uint16, send func() ([]byte, error)) ([]byte, error) {
+	if !s.policy.retries(code) {
+		return send()
+	}
+	start := clk.Now()
+	for attempts := 1; ; attempts++ {
+		results, err := send()
+		var e *Error
+		if !errors.As(err, &e) || e.ExceptionCode != ACKNOWLEDGE {
+			return results, err
+		}
+		if clk.Now().Sub(start)+s.policy.Delay > s.policy.MaxWait {
+			exhausted := &RetryExhaustedError{Attempts: attempts, Last: err}
+			if s.policy.OnGiveUp != nil {
+				s.policy.OnGiveUp(code, address, exhausted)
+			}
+			return nil, exhausted
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-clk.After(s.policy.Delay):
+		}
+	}
+}
+
+func (s *acknowledgeSlaver) ReadCoils(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.do(ctx, READ_COILS, address, func() ([]byte, error) { return s.Slaver.ReadCoils(ctx, address, quantity) })
+}
+
+func (s *acknowledgeSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.do(ctx, READ_DISCRETE_INPUTS, address, func() ([]byte, error) { return s.Slaver.ReadDiscreteInputs(ctx, address, quantity) })
+}
+
+func (s *acknowledgeSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.do(ctx, READ_HOLDING_REGISTERS, address, func() ([]byte, error) { return s.Slaver.ReadHoldingRegisters(ctx, address, quantity) })
+}
+
+func (s *acknowledgeSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.do(ctx, READ_INPUT_REGISTERS, address, func() ([]byte, error) { return s.Slaver.ReadInputRegisters(ctx, address, quantity) })
+}
+
+func (s *acknowledgeSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) (results []byte, err error) {
+	return s.do(ctx, WRITE_SINGLE_COIL, address, func() ([]byte, error) { return s.Slaver.WriteSingleCoil(ctx, address, value) })
+}
+
+func (s *acknowledgeSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) (results []byte, err error) {
+	return s.do(ctx, WRITE_SINGLE_REGISTER, address, func() ([]byte, error) { return s.Slaver.WriteSingleRegister(ctx, address, value) })
+}
+
+func (s *acknowledgeSlaver) ReadExceptionStatus(ctx context.Context) (results []byte, err error) {
+	return s.do(ctx, READ_EXCEPTION_STATUS, 0, func() ([]byte, error) { return s.Slaver.ReadExceptionStatus(ctx) })
+}
+
+func (s *acknowledgeSlaver) Diagnostics(ctx context.Context, subFunc uint16, value []byte) (results []byte, err error) {
+	return s.do(ctx, DIAGNOSTICS, 0, func() ([]byte, error) { return s.Slaver.Diagnostics(ctx, subFunc, value) })
+}
+
+func (s *acknowledgeSlaver) GetCommEventCounter(ctx context.Context) (results []byte, err error) {
+	return s.do(ctx, GET_COMM_EVENT_COUNTER, 0, func() ([]byte, error) { return s.Slaver.GetCommEventCounter(ctx) })
+}
+
+func (s *acknowledgeSlaver) GetCommEventLog(ctx context.Context) (results []byte, err error) {
+	return s.do(ctx, GET_COMM_EVENT_LOG, 0, func() ([]byte, error) { return s.Slaver.GetCommEventLog(ctx) })
+}
+
+func (s *acknowledgeSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	return s.do(ctx, WRITE_MULTIPLE_COILS, address, func() ([]byte, error) { return s.Slaver.WriteMultipleCoils(ctx, address, quantity, value) })
+}
+
+func (s *acknowledgeSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	return s.do(ctx, WRITE_MULTIPLE_REGISTERS, address, func() ([]byte, error) { return s.Slaver.WriteMultipleregisters(ctx, address, quantity, value) })
+}
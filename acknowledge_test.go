@@ -0,0 +1,208 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+// acknowledgeSlaver 前n次写单个寄存器返回ACKNOWLEDGE异常,之后返回成功
+func acknowledgeSlaver(n int, calls *int) *modbustest.MockSlaver {
+	return &modbustest.MockSlaver{WriteSingleRegisterFunc: func(ctx context.Context, address, value uint16) ([]byte, error) {
+		*calls++
+		if n < 0 || *calls <= n {
+			return nil, &modbus.Error{FunctionCode: modbus.WRITE_SINGLE_REGISTER, ExceptionCode: modbus.ACKNOWLEDGE}
+		}
+		return []byte{0x00, 0x01, 0x00, 0x02}, nil
+	}}
+}
+
+type slaverResult struct {
+	results []byte
+	err     error
+}
+
+func TestRetryOnAcknowledge(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	calls := 0
+	s, err := modbus.RetryOnAcknowledge(acknowledgeSlaver(2, &calls), modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Minute, RetryWrites: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan slaverResult, 1)
+	go func() {
+		results, err := s.WriteSingleRegister(context.Background(), 1, 2)
+		done <- slaverResult{results, err}
+	}()
+	for i := 0; i < 2; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+	}
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("unexpected error: %v", r.err)
+	}
+	if calls != 3 || len(r.results) != 4 {
+		t.Fatalf("calls = %v, results = % X", calls, r.results)
+	}
+}
+
+func TestRetryOnAcknowledgeMaxWait(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	calls := 0
+	s, err := modbus.RetryOnAcknowledge(acknowledgeSlaver(-1, &calls), modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: 5 * time.Second, RetryWrites: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan slaverResult, 1)
+	go func() {
+		results, err := s.WriteSingleRegister(context.Background(), 1, 2)
+		done <- slaverResult{results, err}
+	}()
+	// 第6次发送时已等待5秒,再等待1秒将超过MaxWait
+	for i := 0; i < 5; i++ {
+		clock.BlockUntil(1)
+		clock.Advance(time.Second)
+	}
+	err = (<-done).err
+	var exhausted *modbus.RetryExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Attempts != 6 || calls != 6 {
+		t.Fatalf("got %v after %v calls, want *RetryExhaustedError after 6 attempts", err, calls)
+	}
+	var e *modbus.Error
+	if !errors.As(err, &e) || e.ExceptionCode != modbus.ACKNOWLEDGE {
+		t.Fatalf("%v does not unwrap to the ACKNOWLEDGE exception", err)
+	}
+}
+
+func TestRetryOnAcknowledgeOnGiveUp(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	type giveUp struct {
+		code    byte
+		address uint16
+		err     error
+	}
+	var gaveUp []giveUp
+	ack := true
+	s, err := modbus.RetryOnAcknowledge(&modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		if ack {
+			return nil, &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.ACKNOWLEDGE}
+		}
+		return nil, &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.ILLEGAL_DATA_ADDRESS}
+	}}, modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Second, OnGiveUp: func(code byte, address uint16, err error) {
+		gaveUp = append(gaveUp, giveUp{code, address, err})
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan slaverResult, 1)
+	go func() {
+		results, err := s.ReadHoldingRegisters(context.Background(), 7, 1)
+		done <- slaverResult{results, err}
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	r := <-done
+	var exhausted *modbus.RetryExhaustedError
+	if !errors.As(r.err, &exhausted) {
+		t.Fatalf("got %v, want *RetryExhaustedError", r.err)
+	}
+	if len(gaveUp) != 1 || gaveUp[0].code != modbus.READ_HOLDING_REGISTERS || gaveUp[0].address != 7 || gaveUp[0].err != r.err {
+		t.Fatalf("OnGiveUp calls = %+v, want one call for function 3 address 7 with the returned error", gaveUp)
+	}
+
+	// 其他异常不是放弃重发,不调用OnGiveUp
+	ack = false
+	if _, err := s.ReadHoldingRegisters(context.Background(), 7, 1); err == nil || len(gaveUp) != 1 {
+		t.Fatalf("got %v with %v OnGiveUp calls, want the exception and no new call", err, len(gaveUp))
+	}
+}
+
+func TestRetryOnAcknowledgeWritesNotRetried(t *testing.T) {
+	calls := 0
+	s, err := modbus.RetryOnAcknowledge(acknowledgeSlaver(-1, &calls), modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e *modbus.Error
+	if _, err := s.WriteSingleRegister(context.Background(), 1, 2); !errors.As(err, &e) || e.ExceptionCode != modbus.ACKNOWLEDGE || calls != 1 {
+		t.Fatalf("got %v after %v calls, want the ACKNOWLEDGE exception without resend", err, calls)
+	}
+}
+
+func TestRetryOnAcknowledgeReads(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	calls := 0
+	s, err := modbus.RetryOnAcknowledge(&modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		calls++
+		if calls == 1 {
+			return nil, &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.ACKNOWLEDGE}
+		}
+		return []byte{0x02, 0x00, 0x01}, nil
+	}}, modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan slaverResult, 1)
+	go func() {
+		results, err := s.ReadHoldingRegisters(context.Background(), 0, 1)
+		done <- slaverResult{results, err}
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if r := <-done; r.err != nil || calls != 2 {
+		t.Fatalf("got %v after %v calls, want success after resend", r.err, calls)
+	}
+}
+
+func TestRetryOnAcknowledgeOtherException(t *testing.T) {
+	calls := 0
+	s, err := modbus.RetryOnAcknowledge(&modbustest.MockSlaver{WriteSingleRegisterFunc: func(ctx context.Context, address, value uint16) ([]byte, error) {
+		calls++
+		return nil, &modbus.Error{FunctionCode: modbus.WRITE_SINGLE_REGISTER, ExceptionCode: modbus.ILLEGAL_DATA_VALUE}
+	}}, modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e *modbus.Error
+	if _, err := s.WriteSingleRegister(context.Background(), 1, 2); !errors.As(err, &e) || calls != 1 {
+		t.Fatalf("got %v after %v calls, want the exception without retry", err, calls)
+	}
+}
+
+func TestRetryOnAcknowledgePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy modbus.AcknowledgePolicy
+		ok     bool
+	}{
+		{"zero delay", modbus.AcknowledgePolicy{Delay: 0, MaxWait: time.Second}, false},
+		{"negative delay", modbus.AcknowledgePolicy{Delay: -time.Second, MaxWait: time.Second}, false},
+		{"max wait shorter than delay", modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Millisecond}, false},
+		{"max wait equal to delay", modbus.AcknowledgePolicy{Delay: time.Second, MaxWait: time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := modbus.RetryOnAcknowledge(&modbustest.MockSlaver{}, tt.policy)
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, modbus.ErrOutOfRange) {
+				t.Fatalf("got %v, want ErrOutOfRange", err)
+			}
+		})
+	}
+}
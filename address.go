@@ -0,0 +1,112 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// 设备的地址空间,各字段为对应数据区允许的最大地址
+// 字段为0时表示不额外限制,即使用协议的完整地址空间[0x0000-0xFFFF]
+type AddressSpace struct {
+	MaxCoil            uint16
+	MaxDiscreteInput   uint16
+	MaxHoldingRegister uint16
+	MaxInputRegister   uint16
+}
+
+// Check 校验功能码code访问的[address, address+quantity-1]是否位于地址空间内,越界时返回ErrOutOfRange
+// 不涉及数据区地址的功能码不做校验
+func (a AddressSpace) Check(code byte, address, quantity uint16) error {
+	var limit uint16
+	var area string
+	switch code {
+	case READ_COILS, WRITE_SINGLE_COIL, WRITE_MULTIPLE_COILS:
+		limit, area = a.MaxCoil, "coil"
+	case READ_DISCRETE_INPUTS:
+		limit, area = a.MaxDiscreteInput, "discrete input"
+	case READ_HOLDING_REGISTERS, WRITE_SINGLE_REGISTER, WRITE_MULTIPLE_REGISTERS, READ_WRITE_MULTIPLE_REGISTERS:
+		limit, area = a.MaxHoldingRegister, "holding register"
+	case READ_INPUT_REGISTERS:
+		limit, area = a.MaxInputRegister, "input register"
+	default:
+		return nil
+	}
+	if limit == 0 {
+		limit = 0xFFFF
+	}
+	if quantity == 0 {
+		quantity = 1
+	}
+	last := uint32(address) + uint32(quantity) - 1
+	if last > uint32(limit) {
+		return fmt.Errorf("%w: %s address range '%v'-'%v' exceeds maximum address '%v'", ErrOutOfRange, area, address, last, limit)
+	}
+	return nil
+}
+
+type addressSpaceSlaver struct {
+	Slaver
+	space AddressSpace
+}
+
+// CheckAddressSpace 包装Slaver,在发送请求前按地址空间校验访问的地址范围
+// 越界的读写在本地返回ErrOutOfRange,不再往返设备得到ILLEGAL_DATA_ADDRESS异常
+func CheckAddressSpace(s Slaver, space AddressSpace) Slaver {
+	return &addressSpaceSlaver{Slaver: s, space: space}
+}
+
+func (s *addressSpaceSlaver) ReadCoils(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	if err = s.space.Check(READ_COILS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.ReadCoils(ctx, address, quantity)
+}
+
+func (s *addressSpaceSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	if err = s.space.Check(READ_DISCRETE_INPUTS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.ReadDiscreteInputs(ctx, address, quantity)
+}
+
+func (s *addressSpaceSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	if err = s.space.Check(READ_HOLDING_REGISTERS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.ReadHoldingRegisters(ctx, address, quantity)
+}
+
+func (s *addressSpaceSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	if err = s.space.Check(READ_INPUT_REGISTERS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.ReadInputRegisters(ctx, address, quantity)
+}
+
+func (s *addressSpaceSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) (results []byte, err error) {
+	if err = s.space.Check(WRITE_SINGLE_COIL, address, 1); err != nil {
+		return nil, err
+	}
+	return s.Slaver.WriteSingleCoil(ctx, address, value)
+}
+
+func (s *addressSpaceSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) (results []byte, err error) {
+	if err = s.space.Check(WRITE_SINGLE_REGISTER, address, 1); err != nil {
+		return nil, err
+	}
+	return s.Slaver.WriteSingleRegister(ctx, address, value)
+}
+
+func (s *addressSpaceSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	if err = s.space.Check(WRITE_MULTIPLE_COILS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.WriteMultipleCoils(ctx, address, quantity, value)
+}
+
+func (s *addressSpaceSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	if err = s.space.Check(WRITE_MULTIPLE_REGISTERS, address, quantity); err != nil {
+		return nil, err
+	}
+	return s.Slaver.WriteMultipleregisters(ctx, address, quantity, value)
+}
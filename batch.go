@@ -0,0 +1,148 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// 地址范围
+type AddressRange struct {
+	Address  uint16
+	Quantity uint16
+}
+
+func (r AddressRange) String() string {
+	return fmt.Sprintf("[%v-%v]", r.Address, uint32(r.Address)+uint32(r.Quantity)-1)
+}
+
+// 分块操作中单个块的失败
+type ChunkError struct {
+	AddressRange
+	Err error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("%v: %v", e.AddressRange, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// 分块写入部分失败,列出成功和失败的地址范围,调用方可只重试失败的块
+type BatchWriteError struct {
+	Succeeded []AddressRange
+	Failed    []*ChunkError
+}
+
+func (e *BatchWriteError) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = f.Error()
+	}
+	return fmt.Sprintf("modbus: %v of %v chunks failed: %s", len(e.Failed), len(e.Failed)+len(e.Succeeded), strings.Join(failed, "; "))
+}
+
+func (e *BatchWriteError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// WriteRegistersLarge 将任意数量的寄存器数据按单帧上限拆分为多个WriteMultipleRegisters(0x10)请求写入
+// value: N*2字节,数据
+// 某个块失败后继续写后续块;上下文结束后剩余块不再发送,均以ctx.Err()记为失败
+// 存在失败块时返回*BatchWriteError
+func WriteRegistersLarge(ctx context.Context, s Slaver, address uint16, value []byte) error {
+	if len(value) == 0 || len(value)%2 != 0 {
+		return fmt.Errorf("%w: register value length '%v' must be a positive even number", ErrOutOfRange, len(value))
+	}
+	total := len(value) / 2
+	if int(address)+total > 0x10000 {
+		return fmt.Errorf("%w: '%v' registers from address '%v' exceed the address space", ErrOutOfRange, total, address)
+	}
+	batch := &BatchWriteError{}
+	for offset := 0; offset < total; offset += int(MAX_WRITE_REGISTERS) {
+		quantity := total - offset
+		if quantity > int(MAX_WRITE_REGISTERS) {
+			quantity = int(MAX_WRITE_REGISTERS)
+		}
+		r := AddressRange{Address: address + uint16(offset), Quantity: uint16(quantity)}
+		if err := ctx.Err(); err != nil {
+			batch.Failed = append(batch.Failed, &ChunkError{AddressRange: r, Err: err})
+			continue
+		}
+		chunk := value[offset*2 : (offset+quantity)*2]
+		if _, err := WriteMultipleRegisters(ctx, s, r.Address, r.Quantity, chunk); err != nil {
+			batch.Failed = append(batch.Failed, &ChunkError{AddressRange: r, Err: err})
+			continue
+		}
+		batch.Succeeded = append(batch.Succeeded, r)
+	}
+	if len(batch.Failed) > 0 {
+		return batch
+	}
+	return nil
+}
+
+// WriteCoilsLarge 将任意数量的线圈状态按单帧上限拆分为多个WriteMultipleCoils(0x0F)请求写入
+// 每个块按自身起始地址独立打包,块内首个线圈对应该块首字节的最低位
+// 某个块失败后继续写后续块;上下文结束后剩余块不再发送,均以ctx.Err()记为失败
+// 存在失败块时返回*BatchWriteError
+func WriteCoilsLarge(ctx context.Context, s Slaver, address uint16, values []bool) error {
+	total := len(values)
+	if total == 0 {
+		return fmt.Errorf("%w: coil quantity must not be zero", ErrOutOfRange)
+	}
+	if int(address)+total > 0x10000 {
+		return fmt.Errorf("%w: '%v' coils from address '%v' exceed the address space", ErrOutOfRange, total, address)
+	}
+	batch := &BatchWriteError{}
+	for offset := 0; offset < total; offset += int(MAX_WRITE_COILS) {
+		quantity := total - offset
+		if quantity > int(MAX_WRITE_COILS) {
+			quantity = int(MAX_WRITE_COILS)
+		}
+		r := AddressRange{Address: address + uint16(offset), Quantity: uint16(quantity)}
+		if err := ctx.Err(); err != nil {
+			batch.Failed = append(batch.Failed, &ChunkError{AddressRange: r, Err: err})
+			continue
+		}
+		chunk := PackCoils(values[offset : offset+quantity])
+		if _, err := WriteMultipleCoils(ctx, s, r.Address, r.Quantity, chunk); err != nil {
+			batch.Failed = append(batch.Failed, &ChunkError{AddressRange: r, Err: err})
+			continue
+		}
+		batch.Succeeded = append(batch.Succeeded, r)
+	}
+	if len(batch.Failed) > 0 {
+		return batch
+	}
+	return nil
+}
+
+// DumpCoils 以最少的读线圈(0x01)请求(每帧最多2000个)读取[start, end]范围内的全部线圈并拼接为一个切片
+// end包含在内;任一块失败时立即返回包含该块地址范围的*ChunkError
+func DumpCoils(ctx context.Context, s Slaver, start, end uint16) ([]bool, error) {
+	if end < start {
+		return nil, fmt.Errorf("%w: end address '%v' before start address '%v'", ErrOutOfRange, end, start)
+	}
+	total := int(end) - int(start) + 1
+	values := make([]bool, 0, total)
+	for offset := 0; offset < total; offset += int(MAX_READ_COILS) {
+		quantity := total - offset
+		if quantity > int(MAX_READ_COILS) {
+			quantity = int(MAX_READ_COILS)
+		}
+		r := AddressRange{Address: start + uint16(offset), Quantity: uint16(quantity)}
+		data, err := Poll(ctx, s, PollRequest{Code: READ_COILS, Address: r.Address, Quantity: r.Quantity})
+		if err != nil {
+			return nil, &ChunkError{AddressRange: r, Err: err}
+		}
+		values = append(values, UnpackCoils(data, r.Quantity)...)
+	}
+	return values, nil
+}
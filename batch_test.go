@@ -0,0 +1,123 @@
+package modbus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+var errChunk = errors.New("chunk failed")
+
+// echo 写多个线圈/寄存器的响应: 起始地址(2) + 数量(2)
+func echo(address, quantity uint16) []byte {
+	return []byte{byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}
+}
+
+type chunkWrite struct {
+	address  uint16
+	quantity uint16
+	value    []byte
+}
+
+func TestWriteRegistersLargePartialFailure(t *testing.T) {
+	var writes []chunkWrite
+	s := &modbustest.MockSlaver{WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		writes = append(writes, chunkWrite{address, quantity, append([]byte(nil), value...)})
+		if len(writes) == 2 {
+			return nil, errChunk
+		}
+		return echo(address, quantity), nil
+	}}
+	value := make([]byte, 300*2)
+	for i := range value {
+		value[i] = byte(i)
+	}
+
+	err := modbus.WriteRegistersLarge(context.Background(), s, 100, value)
+	var batch *modbus.BatchWriteError
+	if !errors.As(err, &batch) {
+		t.Fatalf("got %v, want *BatchWriteError", err)
+	}
+	wantRanges := []modbus.AddressRange{{Address: 100, Quantity: 123}, {Address: 223, Quantity: 123}, {Address: 346, Quantity: 54}}
+	for i, w := range writes {
+		if w.address != wantRanges[i].Address || w.quantity != wantRanges[i].Quantity {
+			t.Fatalf("chunk %v wrote %v registers at %v, want %v", i, w.quantity, w.address, wantRanges[i])
+		}
+		offset := int(w.address-100) * 2
+		if !bytes.Equal(w.value, value[offset:offset+int(w.quantity)*2]) {
+			t.Fatalf("chunk %v carries the wrong slice of value", i)
+		}
+	}
+	if want := []modbus.AddressRange{wantRanges[0], wantRanges[2]}; !reflect.DeepEqual(batch.Succeeded, want) {
+		t.Fatalf("succeeded = %v, want %v", batch.Succeeded, want)
+	}
+	if len(batch.Failed) != 1 || batch.Failed[0].AddressRange != wantRanges[1] || !errors.Is(batch.Failed[0].Err, errChunk) {
+		t.Fatalf("failed = %v, want %v with the chunk error", batch.Failed, wantRanges[1])
+	}
+	if !errors.Is(err, errChunk) {
+		t.Fatal("errors.Is does not reach the chunk error")
+	}
+}
+
+func TestWriteRegistersLargeContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	writes := 0
+	s := &modbustest.MockSlaver{WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		writes++
+		cancel()
+		return echo(address, quantity), nil
+	}}
+
+	err := modbus.WriteRegistersLarge(ctx, s, 0, make([]byte, 250*2))
+	var batch *modbus.BatchWriteError
+	if !errors.As(err, &batch) || writes != 1 {
+		t.Fatalf("got %v after %v writes", err, writes)
+	}
+	if want := []modbus.AddressRange{{Address: 0, Quantity: 123}}; !reflect.DeepEqual(batch.Succeeded, want) {
+		t.Fatalf("succeeded = %v, want %v", batch.Succeeded, want)
+	}
+	if len(batch.Failed) != 2 || !errors.Is(batch.Failed[0].Err, context.Canceled) || batch.Failed[1].AddressRange != (modbus.AddressRange{Address: 246, Quantity: 4}) {
+		t.Fatalf("failed = %v", batch.Failed)
+	}
+}
+
+func TestWriteCoilsLargeChunks(t *testing.T) {
+	var writes []chunkWrite
+	s := &modbustest.MockSlaver{WriteMultipleCoilsFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		writes = append(writes, chunkWrite{address, quantity, append([]byte(nil), value...)})
+		if len(writes) == 1 {
+			return nil, errChunk
+		}
+		return echo(address, quantity), nil
+	}}
+	values := make([]bool, 2000)
+	for i := range values {
+		values[i] = i%3 == 0
+	}
+
+	err := modbus.WriteCoilsLarge(context.Background(), s, 10, values)
+	var batch *modbus.BatchWriteError
+	if !errors.As(err, &batch) {
+		t.Fatalf("got %v, want *BatchWriteError", err)
+	}
+	if len(writes) != 2 {
+		t.Fatalf("%v chunks written, want 2", len(writes))
+	}
+	// 第二块从第1968个线圈开始独立打包,首个线圈对应首字节的最低位
+	second := writes[1]
+	if second.address != 10+1968 || second.quantity != 32 || !bytes.Equal(second.value, modbus.PackCoils(values[1968:])) {
+		t.Fatalf("second chunk: %v coils at %v, % X", second.quantity, second.address, second.value)
+	}
+	if want := []modbus.AddressRange{{Address: 1978, Quantity: 32}}; !reflect.DeepEqual(batch.Succeeded, want) {
+		t.Fatalf("succeeded = %v, want %v", batch.Succeeded, want)
+	}
+	if len(batch.Failed) != 1 || batch.Failed[0].AddressRange != (modbus.AddressRange{Address: 10, Quantity: 1968}) {
+		t.Fatalf("failed = %v", batch.Failed)
+	}
+}
@@ -0,0 +1,44 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// MAX_BCD_REGISTERS ReadBCD单次读取的最大寄存器数(16位十进制数字)
+const MAX_BCD_REGISTERS uint16 = 4
+
+// RegistersToBCD 将寄存器数据按BCD码解码为十进制整数,每个半字节表示一位十进制数字,高位在前
+// 任一半字节大于9时返回错误;data最多9字节(18位十进制数字)
+func RegistersToBCD(data []byte) (uint64, error) {
+	if len(data) > 9 {
+		return 0, fmt.Errorf("%w: '%v' BCD digits overflow uint64", ErrOutOfRange, len(data)*2)
+	}
+	var v uint64
+	for i, b := range data {
+		for _, digit := range [2]byte{b >> 4, b & 0x0F} {
+			if digit > 9 {
+				return 0, fmt.Errorf("%w: invalid BCD digit '0x%X' in byte %v ('0x%02X')", ErrInvalidResponse, digit, i, b)
+			}
+			v = v*10 + uint64(digit)
+		}
+	}
+	return v, nil
+}
+
+// ReadBCD 读取远程设备中quantity个连续保持寄存器,并按BCD码解码为十进制整数
+// quantity为[1-4],更多的寄存器超出uint64可表示的位数,在发送请求前返回ErrOutOfRange
+func ReadBCD(ctx context.Context, s Slaver, address, quantity uint16) (uint64, error) {
+	if quantity == 0 || quantity > MAX_BCD_REGISTERS {
+		return 0, fmt.Errorf("%w: BCD register quantity '%v' must be between '1' and '%v'", ErrOutOfRange, quantity, MAX_BCD_REGISTERS)
+	}
+	results, err := s.ReadHoldingRegisters(ctx, address, quantity)
+	if err != nil {
+		return 0, err
+	}
+	data, err := registerData(results, quantity)
+	if err != nil {
+		return 0, err
+	}
+	return RegistersToBCD(data)
+}
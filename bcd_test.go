@@ -0,0 +1,47 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestRegistersToBCD(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint64
+		err  error
+	}{
+		{[]byte{0x12, 0x34}, 1234, nil},
+		{[]byte{0x00, 0x09, 0x87, 0x65}, 98765, nil},
+		{[]byte{0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99, 0x99}, 999999999999999999, nil},
+		{[]byte{0x12, 0x3A}, 0, modbus.ErrInvalidResponse},
+		{make([]byte, 10), 0, modbus.ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		got, err := modbus.RegistersToBCD(tt.data)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("RegistersToBCD(% X) = (%v, %v), want (%v, %v)", tt.data, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestReadBCDQuantity(t *testing.T) {
+	sent := false
+	s := &modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		sent = true
+		return append([]byte{byte(quantity * 2)}, make([]byte, quantity*2)...), nil
+	}}
+	for _, quantity := range []uint16{0, 5, 10} {
+		sent = false
+		if _, err := modbus.ReadBCD(context.Background(), s, 0, quantity); !errors.Is(err, modbus.ErrOutOfRange) || sent {
+			t.Fatalf("ReadBCD quantity %v: err = %v, sent = %v", quantity, err, sent)
+		}
+	}
+	if _, err := modbus.ReadBCD(context.Background(), s, 0, 4); err != nil {
+		t.Fatalf("ReadBCD quantity 4: %v", err)
+	}
+}
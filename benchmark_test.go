@@ -0,0 +1,89 @@
+package modbus_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+// 基准测试使用的寄存器数量,覆盖单个寄存器到单帧读取上限
+var benchmarkRegisterCounts = []int{1, 16, 125}
+
+func BenchmarkCRC16(b *testing.B) {
+	for _, n := range benchmarkRegisterCounts {
+		// 从站地址(1) + 功能码(1) + 字节数(1) + 数据(n*2)
+		frame := make([]byte, 3+n*2)
+		b.Run(fmt.Sprintf("registers=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(frame)))
+			for i := 0; i < b.N; i++ {
+				modbus.CRC16(frame)
+			}
+		})
+	}
+}
+
+func BenchmarkPackCoils(b *testing.B) {
+	for _, n := range []int{8, 256, int(modbus.MAX_WRITE_COILS)} {
+		values := make([]bool, n)
+		for i := range values {
+			values[i] = i%3 == 0
+		}
+		b.Run(fmt.Sprintf("coils=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				modbus.PackCoils(values)
+			}
+		})
+	}
+}
+
+func BenchmarkUnpackCoils(b *testing.B) {
+	for _, n := range []int{8, 256, int(modbus.MAX_READ_COILS)} {
+		data := make([]byte, (n+7)/8)
+		b.Run(fmt.Sprintf("coils=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				modbus.UnpackCoils(data, uint16(n))
+			}
+		})
+	}
+}
+
+func BenchmarkRegistersToUint32(b *testing.B) {
+	data := []byte{0x11, 0x22, 0x33, 0x44}
+	for name, order := range map[string]modbus.ByteOrder{"ABCD": modbus.ABCD, "DCBA": modbus.DCBA} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				modbus.RegistersToUint32(data, order)
+			}
+		})
+	}
+}
+
+func BenchmarkDataTypeDecode(b *testing.B) {
+	for _, n := range benchmarkRegisterCounts {
+		data := make([]byte, n*2)
+		b.Run(fmt.Sprintf("registers=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				// 按寄存器逐个解码,模拟对整块读取结果的解码
+				for off := 0; off+2 <= len(data); off += 2 {
+					modbus.TYPE_UINT16.Decode(data[off:off+2], modbus.ABCD)
+				}
+			}
+		})
+	}
+	for name, typ := range map[string]modbus.DataType{"float32": modbus.TYPE_FLOAT32, "float64": modbus.TYPE_FLOAT64} {
+		data := make([]byte, typ.Registers()*2)
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				typ.Decode(data, modbus.CDAB)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package modbus
+
+import (
+	"context"
+	"math/bits"
+)
+
+// BitSet 紧凑的位集合,第i位对应起始地址偏移i的线圈/离散输入
+type BitSet struct {
+	words []uint64
+	n     int
+}
+
+// NewBitSet 由线圈状态字节(Modbus位序,首字节最低位为第0位)创建长度为n的BitSet
+func NewBitSet(data []byte, n int) *BitSet {
+	b := &BitSet{words: make([]uint64, (n+63)/64), n: n}
+	for i := 0; i < n && i/8 < len(data); i++ {
+		if data[i/8]&(1<<(uint(i)%8)) != 0 {
+			b.words[i/64] |= 1 << (uint(i) % 64)
+		}
+	}
+	return b
+}
+
+// Len 位的数量
+func (b *BitSet) Len() int {
+	return b.n
+}
+
+// Test 第i位是否为1,越界返回false
+func (b *BitSet) Test(i int) bool {
+	if i < 0 || i >= b.n {
+		return false
+	}
+	return b.words[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// Count 为1的位的数量
+func (b *BitSet) Count() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Range 按从低到高的顺序对每个为1的位调用fn,fn返回false时停止
+func (b *BitSet) Range(fn func(i int) bool) {
+	for wi, w := range b.words {
+		for w != 0 {
+			i := wi*64 + bits.TrailingZeros64(w)
+			if !fn(i) {
+				return
+			}
+			w &= w - 1
+		}
+	}
+}
+
+// Diff 返回与other不同的位(异或),用于比较两次扫描间的状态变化;长度取两者中较大者
+func (b *BitSet) Diff(other *BitSet) *BitSet {
+	n := b.n
+	if other.n > n {
+		n = other.n
+	}
+	d := &BitSet{words: make([]uint64, (n+63)/64), n: n}
+	for i := range d.words {
+		var x, y uint64
+		if i < len(b.words) {
+			x = b.words[i]
+		}
+		if i < len(other.words) {
+			y = other.words[i]
+		}
+		d.words[i] = x ^ y
+	}
+	return d
+}
+
+// ReadCoilsBitSet 读取远程设备中线圈的状态并返回BitSet
+func ReadCoilsBitSet(ctx context.Context, s Slaver, address, quantity uint16) (*BitSet, error) {
+	data, err := Poll(ctx, s, PollRequest{Code: READ_COILS, Address: address, Quantity: quantity})
+	if err != nil {
+		return nil, err
+	}
+	return NewBitSet(data, int(quantity)), nil
+}
+
+// ReadDiscreteInputsBitSet 读取远程设备中离散输入的状态并返回BitSet
+func ReadDiscreteInputsBitSet(ctx context.Context, s Slaver, address, quantity uint16) (*BitSet, error) {
+	data, err := Poll(ctx, s, PollRequest{Code: READ_DISCRETE_INPUTS, Address: address, Quantity: quantity})
+	if err != nil {
+		return nil, err
+	}
+	return NewBitSet(data, int(quantity)), nil
+}
@@ -0,0 +1,107 @@
+package modbus
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cacheKey struct {
+	code     byte
+	address  uint16
+	quantity uint16
+}
+
+type cacheEntry struct {
+	results []byte
+	expires time.Time
+}
+
+type cacheSlaver struct {
+	Slaver
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	// 每次写入后递增,避免写入期间发出的读请求把旧值放回缓存
+	generation uint64
+}
+
+// WithCache 包装Slaver,在ttl内对相同的读线圈/离散输入/保持寄存器/输入寄存器请求直接返回缓存的响应
+// 写线圈或保持寄存器时(无论成功与否)清除与写入地址范围重叠的缓存,保证写后读到的是设备上的值
+func WithCache(s Slaver, ttl time.Duration) Slaver {
+	return &cacheSlaver{Slaver: s, ttl: ttl, entries: make(map[cacheKey]cacheEntry)}
+}
+
+func (s *cacheSlaver) read(ctx context.Context, key cacheKey, read func(ctx context.Context, address, quantity uint16) ([]byte, error)) ([]byte, error) {
+	now := clk.Now()
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return append([]byte(nil), e.results...), nil
+	}
+	results, err := read(ctx, key.address, key.quantity)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if s.generation == generation {
+		s.entries[key] = cacheEntry{results: append([]byte(nil), results...), expires: now.Add(s.ttl)}
+	}
+	s.mu.Unlock()
+	return results, nil
+}
+
+// invalidate 清除功能码code下与[address, address+quantity-1]重叠的缓存
+func (s *cacheSlaver) invalidate(code byte, address, quantity uint16) {
+	start, end := uint32(address), uint32(address)+uint32(quantity)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generation++
+	for key := range s.entries {
+		if key.code != code {
+			continue
+		}
+		if uint32(key.address) < end && start < uint32(key.address)+uint32(key.quantity) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+func (s *cacheSlaver) ReadCoils(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.read(ctx, cacheKey{READ_COILS, address, quantity}, s.Slaver.ReadCoils)
+}
+
+func (s *cacheSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.read(ctx, cacheKey{READ_DISCRETE_INPUTS, address, quantity}, s.Slaver.ReadDiscreteInputs)
+}
+
+func (s *cacheSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.read(ctx, cacheKey{READ_HOLDING_REGISTERS, address, quantity}, s.Slaver.ReadHoldingRegisters)
+}
+
+func (s *cacheSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.read(ctx, cacheKey{READ_INPUT_REGISTERS, address, quantity}, s.Slaver.ReadInputRegisters)
+}
+
+func (s *cacheSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) (results []byte, err error) {
+	defer s.invalidate(READ_COILS, address, 1)
+	return s.Slaver.WriteSingleCoil(ctx, address, value)
+}
+
+func (s *cacheSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) (results []byte, err error) {
+	defer s.invalidate(READ_HOLDING_REGISTERS, address, 1)
+	return s.Slaver.WriteSingleRegister(ctx, address, value)
+}
+
+func (s *cacheSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	defer s.invalidate(READ_COILS, address, quantity)
+	return s.Slaver.WriteMultipleCoils(ctx, address, quantity, value)
+}
+
+func (s *cacheSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	defer s.invalidate(READ_HOLDING_REGISTERS, address, quantity)
+	return s.Slaver.WriteMultipleregisters(ctx, address, quantity, value)
+}
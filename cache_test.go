@@ -0,0 +1,126 @@
+package modbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+// countingRegisters 返回统计读保持寄存器次数的MockSlaver,写多个寄存器直接成功
+func countingRegisters(reads map[uint16]int) *modbustest.MockSlaver {
+	return &modbustest.MockSlaver{
+		ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			reads[address]++
+			return append([]byte{byte(quantity * 2)}, make([]byte, quantity*2)...), nil
+		},
+		WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+			return echo(address, quantity), nil
+		},
+	}
+}
+
+func TestWithCacheTTL(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	reads := make(map[uint16]int)
+	s := modbus.WithCache(countingRegisters(reads), time.Second)
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.ReadHoldingRegisters(context.Background(), 0, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if reads[0] != 1 {
+		t.Fatalf("%v reads within ttl, want 1", reads[0])
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	s.ReadHoldingRegisters(context.Background(), 0, 2)
+	if reads[0] != 1 {
+		t.Fatalf("%v reads just before expiry, want 1", reads[0])
+	}
+	clock.Advance(time.Millisecond)
+	s.ReadHoldingRegisters(context.Background(), 0, 2)
+	if reads[0] != 2 {
+		t.Fatalf("%v reads after expiry, want 2", reads[0])
+	}
+}
+
+func TestWithCacheWriteInvalidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  uint16
+		quantity uint16
+		reread   bool
+	}{
+		{"overlapping head", 8, 3, true},
+		{"overlapping tail", 19, 5, true},
+		{"covering", 0, 100, true},
+		{"disjoint before", 0, 10, false},
+		{"disjoint after", 20, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := make(map[uint16]int)
+			s := modbus.WithCache(countingRegisters(reads), time.Hour)
+			// 缓存覆盖寄存器10-19
+			s.ReadHoldingRegisters(context.Background(), 10, 10)
+			if _, err := s.WriteMultipleregisters(context.Background(), tt.address, tt.quantity, make([]byte, tt.quantity*2)); err != nil {
+				t.Fatal(err)
+			}
+			s.ReadHoldingRegisters(context.Background(), 10, 10)
+			want := 1
+			if tt.reread {
+				want = 2
+			}
+			if reads[10] != want {
+				t.Fatalf("%v reads after writing %v+%v, want %v", reads[10], tt.address, tt.quantity, want)
+			}
+		})
+	}
+}
+
+func TestWithCacheWriteDuringRead(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	reads := 0
+	s := modbus.WithCache(&modbustest.MockSlaver{
+		ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			reads++
+			if reads == 1 {
+				close(started)
+				<-release
+			}
+			return []byte{0x02, 0x00, byte(reads)}, nil
+		},
+		WriteSingleRegisterFunc: func(ctx context.Context, address, value uint16) ([]byte, error) {
+			return []byte{0x00, 0x00, 0x00, 0x01}, nil
+		},
+	}, time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.ReadHoldingRegisters(context.Background(), 0, 1)
+		done <- err
+	}()
+	<-started
+	// 读请求发出后、返回前写入,读到的可能是写入前的值,不能进入缓存
+	if _, err := s.WriteSingleRegister(context.Background(), 0, 1); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := s.ReadHoldingRegisters(context.Background(), 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reads != 2 || results[2] != 2 {
+		t.Fatalf("reads = %v, results = % X, want a fresh read after the write", reads, results)
+	}
+}
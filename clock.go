@@ -0,0 +1,18 @@
+package modbus
+
+import "time"
+
+// clock 抽象时间相关操作,便于在测试中替换为可控的时钟来验证超时与退避逻辑
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock 基于time包的真实时钟
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clk 包内使用的时钟,测试中可通过export_test.go中的SetClock替换
+var clk clock = realClock{}
@@ -0,0 +1,90 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// PackCoils 将线圈状态按Modbus位序打包为字节: 第1个线圈对应首字节的最低位
+// 线圈数量不是8的整数倍时,末字节未使用的高位补0
+func PackCoils(values []bool) []byte {
+	data := make([]byte, (len(values)+7)/8)
+	for i, v := range values {
+		if v {
+			data[i/8] |= 1 << (uint(i) % 8)
+		}
+	}
+	return data
+}
+
+// UnpackCoils 将线圈状态字节按Modbus位序展开为quantity个线圈状态,与PackCoils互逆
+func UnpackCoils(data []byte, quantity uint16) []bool {
+	values := make([]bool, quantity)
+	for i := range values {
+		if i/8 < len(data) {
+			values[i] = data[i/8]&(1<<(uint(i)%8)) != 0
+		}
+	}
+	return values
+}
+
+// ValidateCoilValues 校验写多个线圈(0x0F)的数据
+// value的长度必须为ceil(quantity/8)字节,且末字节中未使用的高位必须为0,否则部分设备会拒绝该请求
+func ValidateCoilValues(quantity uint16, value []byte) error {
+	if n := (int(quantity) + 7) / 8; len(value) != n {
+		return fmt.Errorf("%w: '%v' bytes of coil values, quantity '%v' needs '%v' bytes", ErrOutOfRange, len(value), quantity, n)
+	}
+	if rem := quantity % 8; rem != 0 {
+		if unused := value[len(value)-1] >> rem; unused != 0 {
+			return fmt.Errorf("%w: unused bits of the last coil byte must be zero, got '0x%02X'", ErrOutOfRange, value[len(value)-1])
+		}
+	}
+	return nil
+}
+
+// WriteCoils 在远程设备中从address开始写入values对应的线圈状态(ON(true)/OFF(false))
+// 通过WriteMultipleCoils(0x0F)发送,末字节未使用的高位补0
+func WriteCoils(ctx context.Context, s Slaver, address uint16, values []bool) error {
+	if len(values) > math.MaxUint16 {
+		return fmt.Errorf("%w: coil quantity '%v'", ErrOutOfRange, len(values))
+	}
+	quantity := uint16(len(values))
+	if err := ValidateQuantity(WRITE_MULTIPLE_COILS, quantity); err != nil {
+		return err
+	}
+	_, err := s.WriteMultipleCoils(ctx, address, quantity, PackCoils(values))
+	return err
+}
+
+// ReadCoilsInto 读取远程设备中线圈的状态,并将线圈状态字节写入调用方提供的dst,返回写入的字节数
+// dst的长度至少为ceil(quantity/8)字节,否则在发送请求前返回错误
+func ReadCoilsInto(ctx context.Context, s Slaver, address, quantity uint16, dst []byte) (int, error) {
+	if err := ValidateQuantity(READ_COILS, quantity); err != nil {
+		return 0, err
+	}
+	if n := (int(quantity) + 7) / 8; len(dst) < n {
+		return 0, fmt.Errorf("%w: destination of '%v' bytes, quantity '%v' needs '%v' bytes", ErrOutOfRange, len(dst), quantity, n)
+	}
+	results, err := s.ReadCoils(ctx, address, quantity)
+	if err != nil {
+		return 0, err
+	}
+	data, err := coilData(results, quantity)
+	if err != nil {
+		return 0, err
+	}
+	return copy(dst, data), nil
+}
+
+// coilData 校验读线圈/离散输入响应(字节数(1) + 状态(N))并返回状态部分
+func coilData(results []byte, quantity uint16) ([]byte, error) {
+	if len(results) < 1 {
+		return nil, fmt.Errorf("%w: empty response", ErrInvalidResponse)
+	}
+	count := int(results[0])
+	if n := (int(quantity) + 7) / 8; count != n || len(results)-1 != count {
+		return nil, &ByteCountError{Declared: count, Actual: len(results) - 1, Expected: n}
+	}
+	return results[1:], nil
+}
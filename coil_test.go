@@ -0,0 +1,58 @@
+package modbus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+// 写多个线圈的响应: 起始地址(2) + 数量(2)
+func echoWriteMultipleCoils(sent *[]byte) *modbustest.MockSlaver {
+	return &modbustest.MockSlaver{WriteMultipleCoilsFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		*sent = append([]byte(nil), value...)
+		return []byte{byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}, nil
+	}}
+}
+
+func TestWriteCoilsPadsLastByte(t *testing.T) {
+	var sent []byte
+	values := []bool{true, true, true, true, true, true, true, true, true}
+	if err := modbus.WriteCoils(context.Background(), echoWriteMultipleCoils(&sent), 0, values); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 9个线圈占2字节,末字节的7个未使用高位为0
+	if want := []byte{0xFF, 0x01}; !bytes.Equal(sent, want) {
+		t.Fatalf("sent % X, want % X", sent, want)
+	}
+}
+
+func TestWriteMultipleCoilsRejectsUnusedBits(t *testing.T) {
+	var sent []byte
+	s := echoWriteMultipleCoils(&sent)
+	tests := []struct {
+		name  string
+		value []byte
+		want  error
+	}{
+		{"padded", []byte{0xFF, 0x01}, nil},
+		{"unused bits set", []byte{0xFF, 0xFF}, modbus.ErrOutOfRange},
+		{"too many bytes", []byte{0xFF, 0xFF, 0xFF}, modbus.ErrOutOfRange},
+		{"too few bytes", []byte{0xFF}, modbus.ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = nil
+			_, err := modbus.WriteMultipleCoils(context.Background(), s, 0, 9, tt.value)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want != nil && sent != nil {
+				t.Fatalf("request was sent: % X", sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type coilPage struct {
+	values  []bool
+	fetched time.Time
+}
+
+// 正在进行的整页读取,同一页的并发访问等待同一次读取完成
+type coilFetch struct {
+	done chan struct{}
+	page *coilPage
+	err  error
+}
+
+// CoilReader 按页懒加载线圈状态,适用于HMI按需渲染大量线圈的场景
+// 访问某个地址时读取其所在的整页并缓存,缓存未过期的页不再发起请求
+type CoilReader struct {
+	s        Slaver
+	pageSize uint16
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	pages    map[uint16]*coilPage
+	fetching map[uint16]*coilFetch
+	// 每次Invalidate后递增,避免清除前发出的读请求把旧页放回缓存
+	generation uint64
+}
+
+// NewCoilReader 创建CoilReader
+// pageSize: 每页线圈数量[1-2000],为0时使用2000
+// ttl: 页缓存的有效期,为0时每次访问都重新读取
+func NewCoilReader(s Slaver, pageSize uint16, ttl time.Duration) (*CoilReader, error) {
+	if pageSize == 0 {
+		pageSize = MAX_READ_COILS
+	}
+	if pageSize > MAX_READ_COILS {
+		return nil, fmt.Errorf("%w: page size '%v' must be between '1' and '%v'", ErrOutOfRange, pageSize, MAX_READ_COILS)
+	}
+	return &CoilReader{s: s, pageSize: pageSize, ttl: ttl, pages: make(map[uint16]*coilPage), fetching: make(map[uint16]*coilFetch)}, nil
+}
+
+// Get 返回address处线圈的状态,所在页未缓存或已过期时读取整页
+// 读取期间不持有锁,其他页的访问不会被阻塞;同一页的并发访问只发起一次读取,
+// 等待中的调用共享该次读取的结果(包括发起读取的调用的上下文错误)
+func (r *CoilReader) Get(ctx context.Context, address uint16) (bool, error) {
+	start := address / r.pageSize * r.pageSize
+	r.mu.Lock()
+	if page, ok := r.pages[start]; ok && clk.Now().Sub(page.fetched) < r.ttl {
+		r.mu.Unlock()
+		return page.values[address-start], nil
+	}
+	if f, ok := r.fetching[start]; ok {
+		r.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-f.done:
+		}
+		if f.err != nil {
+			return false, f.err
+		}
+		return f.page.values[address-start], nil
+	}
+	f := &coilFetch{done: make(chan struct{})}
+	r.fetching[start] = f
+	generation := r.generation
+	r.mu.Unlock()
+
+	f.page, f.err = r.fetch(ctx, start)
+	r.mu.Lock()
+	if r.fetching[start] == f {
+		delete(r.fetching, start)
+	}
+	if f.err == nil && r.generation == generation {
+		r.pages[start] = f.page
+	}
+	r.mu.Unlock()
+	close(f.done)
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.page.values[address-start], nil
+}
+
+// fetch 读取从start开始的一整页线圈
+func (r *CoilReader) fetch(ctx context.Context, start uint16) (*coilPage, error) {
+	quantity := r.pageSize
+	if rest := 0x10000 - int(start); rest < int(quantity) {
+		quantity = uint16(rest)
+	}
+	results, err := r.s.ReadCoils(ctx, start, quantity)
+	if err != nil {
+		return nil, err
+	}
+	data, err := coilData(results, quantity)
+	if err != nil {
+		return nil, err
+	}
+	return &coilPage{values: UnpackCoils(data, quantity), fetched: clk.Now()}, nil
+}
+
+// Invalidate 清除所有缓存的页
+func (r *CoilReader) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	r.pages = make(map[uint16]*coilPage)
+	r.fetching = make(map[uint16]*coilFetch)
+}
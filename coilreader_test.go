@@ -0,0 +1,136 @@
+package modbus_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestCoilReaderFetchDoesNotBlockCachedPages(t *testing.T) {
+	release := make(chan struct{})
+	s := &modbustest.MockSlaver{ReadCoilsFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		if address == 0 {
+			<-release
+		}
+		n := (int(quantity) + 7) / 8
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = 0xFF
+		}
+		return append([]byte{byte(n)}, data...), nil
+	}}
+	r, err := modbus.NewCoilReader(s, 16, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := r.Get(ctx, 16); err != nil {
+		t.Fatal(err)
+	}
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := r.Get(ctx, 0)
+		slow <- err
+	}()
+
+	got := make(chan bool, 1)
+	go func() {
+		v, _ := r.Get(ctx, 17)
+		got <- v
+	}()
+	select {
+	case v := <-got:
+		if !v {
+			t.Fatal("cached coil 17 should be ON")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cached page blocked by a fetch of another page")
+	}
+
+	close(release)
+	if err := <-slow; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCoilReaderConcurrentGetFetchesOnce(t *testing.T) {
+	var reads int32
+	release := make(chan struct{})
+	s := &modbustest.MockSlaver{ReadCoilsFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		atomic.AddInt32(&reads, 1)
+		<-release
+		n := (int(quantity) + 7) / 8
+		return append([]byte{byte(n)}, make([]byte, n)...), nil
+	}}
+	r, err := modbus.NewCoilReader(s, 64, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(address uint16) {
+			defer wg.Done()
+			_, err := r.Get(context.Background(), address)
+			errs <- err
+		}(uint16(i))
+	}
+	for atomic.LoadInt32(&reads) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(&reads); n != 1 {
+		t.Fatalf("%v ReadCoils for one cold page, want 1", n)
+	}
+}
+
+func TestCoilReaderInvalidateDuringFetch(t *testing.T) {
+	release := make(chan struct{})
+	var reads int32
+	s := &modbustest.MockSlaver{ReadCoilsFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		if atomic.AddInt32(&reads, 1) == 1 {
+			<-release
+		}
+		n := (int(quantity) + 7) / 8
+		return append([]byte{byte(n)}, make([]byte, n)...), nil
+	}}
+	r, err := modbus.NewCoilReader(s, 8, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Get(context.Background(), 0)
+		done <- err
+	}()
+	for atomic.LoadInt32(&reads) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	r.Invalidate()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	// 清除前发起的读取结果不进入缓存
+	if _, err := r.Get(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&reads); n != 2 {
+		t.Fatalf("%v ReadCoils, want 2", n)
+	}
+}
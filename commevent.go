@@ -0,0 +1,103 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// 通信事件类型
+type CommEventType byte
+
+const (
+	COMM_EVENT_RECEIVE     CommEventType = iota // 远程设备接收事件(bit7=1)
+	COMM_EVENT_SEND                             // 远程设备发送事件(bit7=0,bit6=1)
+	COMM_EVENT_LISTEN_ONLY                      // 进入只听模式(0x04)
+	COMM_EVENT_RESTART                          // 通信重启(0x00)
+	COMM_EVENT_UNKNOWN                          // 未定义的事件字节
+)
+
+// 通信事件,由事件日志中的一个事件字节解码得到
+type CommEvent struct {
+	Raw  byte
+	Type CommEventType
+	// 接收事件
+	CommunicationError bool // bit1 通信错误
+	CharacterOverrun   bool // bit4 字符溢出
+	Broadcast          bool // bit6 收到广播
+	// 接收/发送事件
+	ListenOnly bool // bit5 当前处于只听模式
+	// 发送事件
+	ReadException  bool // bit0 发送了读异常(异常码1-3)
+	AbortException bool // bit1 发送了从站终止异常(异常码4)
+	BusyException  bool // bit2 发送了从站忙异常(异常码5-6)
+	ProgramNAK     bool // bit3 发送了从站程序NAK异常(异常码7)
+	WriteTimeout   bool // bit4 发生了写超时错误
+}
+
+// NewCommEvent 按规范解码一个通信事件字节
+func NewCommEvent(b byte) CommEvent {
+	e := CommEvent{Raw: b}
+	switch {
+	case b&0x80 != 0:
+		e.Type = COMM_EVENT_RECEIVE
+		e.CommunicationError = b&0x02 != 0
+		e.CharacterOverrun = b&0x10 != 0
+		e.ListenOnly = b&0x20 != 0
+		e.Broadcast = b&0x40 != 0
+	case b&0x40 != 0:
+		e.Type = COMM_EVENT_SEND
+		e.ReadException = b&0x01 != 0
+		e.AbortException = b&0x02 != 0
+		e.BusyException = b&0x04 != 0
+		e.ProgramNAK = b&0x08 != 0
+		e.WriteTimeout = b&0x10 != 0
+		e.ListenOnly = b&0x20 != 0
+	case b == 0x04:
+		e.Type = COMM_EVENT_LISTEN_ONLY
+	case b == 0x00:
+		e.Type = COMM_EVENT_RESTART
+	default:
+		e.Type = COMM_EVENT_UNKNOWN
+	}
+	return e
+}
+
+// 获取通信事件记录(0x0C)的响应
+type CommEventLog struct {
+	Status       uint16
+	EventCount   uint16
+	MessageCount uint16
+	// 事件,按规范顺序最新的在前
+	Events []CommEvent
+}
+
+// ParseCommEventLog 解析获取通信事件记录(0x0C)的响应数据
+// 字节数(1) + 状态字(2) + 事件计数(2) + 消息计数(2) + 事件(0-64)
+func ParseCommEventLog(results []byte) (*CommEventLog, error) {
+	if len(results) < 7 {
+		return nil, fmt.Errorf("%w: comm event log response of '%v' bytes", ErrInvalidResponse, len(results))
+	}
+	if count := int(results[0]); count < 6 || len(results)-1 != count {
+		return nil, fmt.Errorf("%w: byte count '%v', payload '%v' bytes", ErrInvalidResponse, count, len(results)-1)
+	}
+	log := &CommEventLog{
+		Status:       binary.BigEndian.Uint16(results[1:]),
+		EventCount:   binary.BigEndian.Uint16(results[3:]),
+		MessageCount: binary.BigEndian.Uint16(results[5:]),
+		Events:       make([]CommEvent, 0, len(results)-7),
+	}
+	for _, b := range results[7:] {
+		log.Events = append(log.Events, NewCommEvent(b))
+	}
+	return log, nil
+}
+
+// ReadCommEventLog 读取远程设备的通信事件记录并解析
+func ReadCommEventLog(ctx context.Context, s Slaver) (*CommEventLog, error) {
+	results, err := s.GetCommEventLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCommEventLog(results)
+}
@@ -0,0 +1,37 @@
+package modbus
+
+import (
+	"context"
+	"time"
+)
+
+// 上下文键使用包内未导出的类型,其他包无法构造相同的键,因此不会与其他上下文值冲突
+type (
+	unitIDKey  struct{}
+	timeoutKey struct{}
+)
+
+// WithUnitID 返回携带单元ID(从站地址)的上下文
+// 打包器在组帧时读取该值,用于单次请求覆盖默认的单元ID
+func WithUnitID(ctx context.Context, id byte) context.Context {
+	return context.WithValue(ctx, unitIDKey{}, id)
+}
+
+// UnitIDFromContext 返回上下文中携带的单元ID,未设置时ok为false
+func UnitIDFromContext(ctx context.Context) (id byte, ok bool) {
+	id, ok = ctx.Value(unitIDKey{}).(byte)
+	return id, ok
+}
+
+// WithTimeout 返回携带单次请求超时时间的上下文
+// 传输层在发送时读取该值,用于单次请求覆盖默认的超时时间
+// 与context.WithTimeout不同,它不会为上下文设置截止时间
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// TimeoutFromContext 返回上下文中携带的超时时间,未设置时ok为false
+func TimeoutFromContext(ctx context.Context) (timeout time.Duration, ok bool) {
+	timeout, ok = ctx.Value(timeoutKey{}).(time.Duration)
+	return timeout, ok
+}
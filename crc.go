@@ -0,0 +1,54 @@
+package modbus
+
+// Modbus RTU CRC-16: 多项式0xA001(0x8005的反射),初值0xFFFF,低字节在前附加到帧尾
+const crcPolynomial uint16 = 0xA001
+
+var crcTable = makeCRCTable(crcPolynomial)
+
+func makeCRCTable(poly uint16) (table [256]uint16) {
+	for i := range table {
+		crc := uint16(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}
+
+// CRC初值
+const crcInit uint16 = 0xFFFF
+
+// CRC16 计算data的Modbus RTU CRC-16
+func CRC16(data []byte) uint16 {
+	return CRC16Update(crcInit, data)
+}
+
+// CRC16Update 在已有的crc上继续累加data,可在逐段写入帧时增量计算,避免写完后再遍历一次
+// 首次调用时crc传入CRC16(nil)即初值0xFFFF
+func CRC16Update(crc uint16, data []byte) uint16 {
+	for _, b := range data {
+		crc = crc>>8 ^ crcTable[byte(crc)^b]
+	}
+	return crc
+}
+
+// AppendCRC 计算frame的CRC并按低字节在前附加到帧尾
+func AppendCRC(frame []byte) []byte {
+	crc := CRC16(frame)
+	return append(frame, byte(crc), byte(crc>>8))
+}
+
+// CheckCRC 校验末尾带CRC的完整RTU帧
+func CheckCRC(frame []byte) bool {
+	if len(frame) < 3 {
+		return false
+	}
+	n := len(frame) - 2
+	crc := CRC16(frame[:n])
+	return frame[n] == byte(crc) && frame[n+1] == byte(crc>>8)
+}
@@ -0,0 +1,91 @@
+package modbus_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+// 规范附录及常见抓包中的RTU帧,末尾两字节为CRC(低字节在前)
+var crcFrames = []struct {
+	name  string
+	frame []byte
+}{
+	{"spec appendix", []byte{0x02, 0x07, 0x41, 0x12}},
+	{"read holding registers", []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x0A, 0xC5, 0xCD}},
+	{"write single coil", []byte{0x11, 0x05, 0x00, 0xAC, 0xFF, 0x00, 0x4E, 0x8B}},
+}
+
+func TestCRC16(t *testing.T) {
+	for _, tt := range crcFrames {
+		body := tt.frame[:len(tt.frame)-2]
+		want := uint16(tt.frame[len(tt.frame)-2]) | uint16(tt.frame[len(tt.frame)-1])<<8
+		if got := modbus.CRC16(body); got != want {
+			t.Errorf("%s: CRC16(% X) = 0x%04X, want 0x%04X", tt.name, body, got, want)
+		}
+		if got := modbus.CRC16Update(modbus.CRC16Update(modbus.CRC16(nil), body[:1]), body[1:]); got != want {
+			t.Errorf("%s: incremental CRC16 = 0x%04X, want 0x%04X", tt.name, got, want)
+		}
+	}
+}
+
+func TestAppendCRC(t *testing.T) {
+	for _, tt := range crcFrames {
+		body := tt.frame[:len(tt.frame)-2]
+		if got := modbus.AppendCRC(append([]byte(nil), body...)); !bytes.Equal(got, tt.frame) {
+			t.Errorf("%s: AppendCRC(% X) = % X, want % X", tt.name, body, got, tt.frame)
+		}
+	}
+}
+
+func TestCheckCRC(t *testing.T) {
+	for _, tt := range crcFrames {
+		if !modbus.CheckCRC(tt.frame) {
+			t.Errorf("%s: CheckCRC(% X) = false", tt.name, tt.frame)
+		}
+		corrupted := append([]byte(nil), tt.frame...)
+		corrupted[0] ^= 0x01
+		if modbus.CheckCRC(corrupted) {
+			t.Errorf("%s: CheckCRC(% X) = true for a corrupted frame", tt.name, corrupted)
+		}
+	}
+	if modbus.CheckCRC([]byte{0x01}) {
+		t.Error("CheckCRC accepted a frame shorter than the CRC")
+	}
+}
+
+// 250字节的写多个线圈(0x0F)RTU帧,1928个线圈:
+// 从站地址(1) + 功能码(1) + 起始地址(2) + 数量(2) + 字节数(1) + 数据(241) + CRC(2)
+var (
+	crcBenchmarkHeader = []byte{0x01, 0x0F, 0x00, 0x00, 0x07, 0x88, 0xF1}
+	crcBenchmarkData   = make([]byte, 241)
+)
+
+func BenchmarkCRCSinglePass(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 256)
+	for i := 0; i < b.N; i++ {
+		frame := append(buf[:0], crcBenchmarkHeader...)
+		crc := modbus.CRC16Update(modbus.CRC16(nil), crcBenchmarkHeader)
+		frame = append(frame, crcBenchmarkData...)
+		crc = modbus.CRC16Update(crc, crcBenchmarkData)
+		frame = append(frame, byte(crc), byte(crc>>8))
+		if len(frame) != 250 {
+			b.Fatalf("frame of %v bytes", len(frame))
+		}
+	}
+}
+
+func BenchmarkCRCTwoPass(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 256)
+	for i := 0; i < b.N; i++ {
+		frame := append(buf[:0], crcBenchmarkHeader...)
+		frame = append(frame, crcBenchmarkData...)
+		frame = modbus.AppendCRC(frame)
+		if len(frame) != 250 {
+			b.Fatalf("frame of %v bytes", len(frame))
+		}
+	}
+}
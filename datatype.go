@@ -0,0 +1,78 @@
+package modbus
+
+import (
+	"fmt"
+	"math"
+)
+
+// 寄存器数据类型
+type DataType byte
+
+const (
+	TYPE_UINT16  DataType = iota // 1个寄存器
+	TYPE_INT16                   // 1个寄存器
+	TYPE_UINT32                  // 2个寄存器
+	TYPE_INT32                   // 2个寄存器
+	TYPE_FLOAT32                 // 2个寄存器,IEEE 754
+	TYPE_UINT64                  // 4个寄存器
+	TYPE_INT64                   // 4个寄存器
+	TYPE_FLOAT64                 // 4个寄存器,IEEE 754
+)
+
+// Registers 该类型占用的寄存器数量,未知类型返回0
+func (t DataType) Registers() uint16 {
+	switch t {
+	case TYPE_UINT16, TYPE_INT16:
+		return 1
+	case TYPE_UINT32, TYPE_INT32, TYPE_FLOAT32:
+		return 2
+	case TYPE_UINT64, TYPE_INT64, TYPE_FLOAT64:
+		return 4
+	}
+	return 0
+}
+
+// Decode 按字节序将寄存器数据解码为对应的Go类型(uint16/int16/uint32/int32/float32/uint64/int64/float64)
+// 16位类型只受BYTE_SWAP影响
+func (t DataType) Decode(data []byte, order ByteOrder) (any, error) {
+	n := int(t.Registers()) * 2
+	if n == 0 {
+		return nil, fmt.Errorf("modbus: unknown data type '%v'", t)
+	}
+	if len(data) < n {
+		return nil, fmt.Errorf("%w: '%v' bytes, need '%v' bytes", ErrInvalidResponse, len(data), n)
+	}
+	switch t {
+	case TYPE_UINT16, TYPE_INT16:
+		b := order.toBigEndian(data[:2])
+		v := uint16(b[0])<<8 | uint16(b[1])
+		if t == TYPE_INT16 {
+			return int16(v), nil
+		}
+		return v, nil
+	case TYPE_UINT32, TYPE_INT32, TYPE_FLOAT32:
+		v, err := RegistersToUint32(data, order)
+		if err != nil {
+			return nil, err
+		}
+		switch t {
+		case TYPE_INT32:
+			return int32(v), nil
+		case TYPE_FLOAT32:
+			return math.Float32frombits(v), nil
+		}
+		return v, nil
+	default:
+		v, err := RegistersToUint64(data, order)
+		if err != nil {
+			return nil, err
+		}
+		switch t {
+		case TYPE_INT64:
+			return int64(v), nil
+		case TYPE_FLOAT64:
+			return math.Float64frombits(v), nil
+		}
+		return v, nil
+	}
+}
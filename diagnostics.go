@@ -0,0 +1,28 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// 诊断(0x08)子功能码
+const (
+	RETURN_DIAGNOSTIC_REGISTER uint16 = 2 // 2(0x02) 返回诊断寄存器
+)
+
+// ReadDiagnosticRegister 读取远程设备的16位诊断寄存器(诊断子功能码0x02)
+func ReadDiagnosticRegister(ctx context.Context, s Slaver) (uint16, error) {
+	results, err := s.Diagnostics(ctx, RETURN_DIAGNOSTIC_REGISTER, []byte{0x00, 0x00})
+	if err != nil {
+		return 0, err
+	}
+	// 子功能码(2) + 诊断寄存器(2)
+	if len(results) != 4 {
+		return 0, fmt.Errorf("%w: diagnostic register response of '%v' bytes, expected '4' bytes", ErrInvalidResponse, len(results))
+	}
+	if sub := binary.BigEndian.Uint16(results); sub != RETURN_DIAGNOSTIC_REGISTER {
+		return 0, fmt.Errorf("%w: sub-function '%v', expected '%v'", ErrResponseMismatch, sub, RETURN_DIAGNOSTIC_REGISTER)
+	}
+	return binary.BigEndian.Uint16(results[2:]), nil
+}
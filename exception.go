@@ -0,0 +1,100 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// 附加了设备自定义异常码描述的错误
+// Err为原始错误,Unwrap返回Err,因此仍可通过errors.As获取*Error
+type DescribedError struct {
+	Err         error
+	Description string
+}
+
+func (e *DescribedError) Error() string {
+	if ex, ok := e.Err.(*Error); ok {
+		return ex.message(e.Description)
+	}
+	return fmt.Sprintf("%v (%s)", e.Err, e.Description)
+}
+
+func (e *DescribedError) Unwrap() error {
+	return e.Err
+}
+
+type exceptionSlaver struct {
+	Slaver
+	descriptions map[byte]string
+}
+
+// WithExceptionDescriptions 包装Slaver,为其返回的异常附加设备自定义异常码的描述
+// 异常码在descriptions中时返回*DescribedError;描述只补充标准异常码以外的异常码,不会覆盖内置的标准描述
+// 原始的*Error不会被修改
+func WithExceptionDescriptions(s Slaver, descriptions map[byte]string) Slaver {
+	m := make(map[byte]string, len(descriptions))
+	for code, desc := range descriptions {
+		m[code] = desc
+	}
+	return &exceptionSlaver{Slaver: s, descriptions: m}
+}
+
+func (s *exceptionSlaver) describe(results []byte, err error) ([]byte, error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		return results, err
+	}
+	if desc, ok := s.descriptions[e.ExceptionCode]; ok {
+		return results, &DescribedError{Err: err, Description: desc}
+	}
+	return results, err
+}
+
+func (s *exceptionSlaver) ReadCoils(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.ReadCoils(ctx, address, quantity))
+}
+
+func (s *exceptionSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.ReadDiscreteInputs(ctx, address, quantity))
+}
+
+func (s *exceptionSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.ReadHoldingRegisters(ctx, address, quantity))
+}
+
+func (s *exceptionSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.ReadInputRegisters(ctx, address, quantity))
+}
+
+func (s *exceptionSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.WriteSingleCoil(ctx, address, value))
+}
+
+func (s *exceptionSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) (results []byte, err error) {
+	return s.describe(s.Slaver.WriteSingleRegister(ctx, address, value))
+}
+
+func (s *exceptionSlaver) ReadExceptionStatus(ctx context.Context) (results []byte, err error) {
+	return s.describe(s.Slaver.ReadExceptionStatus(ctx))
+}
+
+func (s *exceptionSlaver) Diagnostics(ctx context.Context, subFunc uint16, value []byte) (results []byte, err error) {
+	return s.describe(s.Slaver.Diagnostics(ctx, subFunc, value))
+}
+
+func (s *exceptionSlaver) GetCommEventCounter(ctx context.Context) (results []byte, err error) {
+	return s.describe(s.Slaver.GetCommEventCounter(ctx))
+}
+
+func (s *exceptionSlaver) GetCommEventLog(ctx context.Context) (results []byte, err error) {
+	return s.describe(s.Slaver.GetCommEventLog(ctx))
+}
+
+func (s *exceptionSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	return s.describe(s.Slaver.WriteMultipleCoils(ctx, address, quantity, value))
+}
+
+func (s *exceptionSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	return s.describe(s.Slaver.WriteMultipleregisters(ctx, address, quantity, value))
+}
@@ -0,0 +1,63 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestWithExceptionDescriptions(t *testing.T) {
+	shared := &modbus.Error{FunctionCode: modbus.WRITE_SINGLE_REGISTER, ExceptionCode: 0x80}
+	scripted := modbustest.NewScriptedSlaver(map[byte]modbustest.Response{
+		modbus.WRITE_SINGLE_REGISTER: {Err: shared},
+		modbus.READ_COILS:            {Err: &modbus.Error{FunctionCode: modbus.READ_COILS, ExceptionCode: modbus.ILLEGAL_DATA_ADDRESS}},
+	})
+	s := modbus.WithExceptionDescriptions(scripted, map[byte]string{
+		0x80:                        "valve jammed",
+		modbus.ILLEGAL_DATA_ADDRESS: "ignored",
+	})
+
+	_, err := s.WriteSingleRegister(context.Background(), 0, 1)
+	if want := "modbus: exception '128' (valve jammed), function '6'"; err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %q", err, want)
+	}
+	var e *modbus.Error
+	if !errors.As(err, &e) || e != shared {
+		t.Fatalf("%v does not unwrap to the original *Error", err)
+	}
+	if want := "modbus: exception '128' (unknown), function '6'"; shared.Error() != want {
+		t.Fatalf("shared error was modified: %q", shared.Error())
+	}
+
+	_, err = s.ReadCoils(context.Background(), 0, 1)
+	if want := "modbus: exception '2' (illegal data address), function '1'"; err == nil || err.Error() != want {
+		t.Fatalf("standard description replaced: got %v", err)
+	}
+}
+
+func TestWithExceptionDescriptionsConcurrent(t *testing.T) {
+	scripted := modbustest.NewScriptedSlaver(map[byte]modbustest.Response{
+		modbus.READ_HOLDING_REGISTERS: {Err: &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: 0x81}},
+	})
+	a := modbus.WithExceptionDescriptions(scripted, map[byte]string{0x81: "device a"})
+	b := modbus.WithExceptionDescriptions(scripted, map[byte]string{0x81: "device b"})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			a.ReadHoldingRegisters(context.Background(), 0, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := b.ReadHoldingRegisters(context.Background(), 0, 1); err.Error() != "modbus: exception '129' (device b), function '3'" {
+				t.Errorf("got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,79 @@
+package modbus
+
+import (
+	"sync"
+	"time"
+)
+
+// SetClock 替换包内使用的时钟,返回恢复原时钟的函数
+// 替换期间的测试不能并行执行
+func SetClock(c *FakeClock) (restore func()) {
+	old := clk
+	clk = c
+	return func() { clk = old }
+}
+
+// FakeClock 只在调用Advance时前进的时钟
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFakeClock 创建当前时间为now的FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t.c
+	}
+	c.timers = append(c.timers, t)
+	return t.c
+}
+
+// Advance 将时钟前进d,并触发到期的定时器
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+// Timers 返回尚未触发的定时器数量,包括等待方已因上下文结束而放弃的定时器
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil 等待直至至少有n个尚未触发的定时器,用于在Advance之前确认被测代码已开始等待
+func (c *FakeClock) BlockUntil(n int) {
+	for c.Timers() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,146 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// 功能码常量 file record access
+const (
+	WRITE_FILE_RECORD byte = 21 // 21(0x15)
+)
+
+// 文件记录相关限制
+const (
+	FILE_RECORD_REFERENCE_TYPE byte   = 6      // 参考类型,固定为6
+	FILE_RECORD_MAX_NUMBER     uint16 = 0x270F // 记录号范围[0x0000-0x270F]
+	FILE_RECORD_MAX_DATA_BYTES int    = 0xFB   // 请求数据(字节数之后)最大长度,使PDU不超过253字节
+)
+
+// 写文件记录(0x15)的子请求
+type FileRecord struct {
+	// 文件号[0x0001-0xFFFF]
+	FileNumber uint16
+	// 起始记录号[0x0000-0x270F]
+	RecordNumber uint16
+	// 记录长度,单位为寄存器(2字节)
+	RecordLength uint16
+	// 记录数据,长度须为RecordLength*2字节
+	Data []byte
+}
+
+// Validate 校验子请求的文件号、记录号以及记录数据长度与声明的记录长度是否一致
+func (r FileRecord) Validate() error {
+	if r.FileNumber == 0 {
+		return fmt.Errorf("%w: file number must not be zero", ErrOutOfRange)
+	}
+	if r.RecordNumber > FILE_RECORD_MAX_NUMBER {
+		return fmt.Errorf("%w: file '%v' record number '%v' exceeds '%v'", ErrOutOfRange, r.FileNumber, r.RecordNumber, FILE_RECORD_MAX_NUMBER)
+	}
+	if r.RecordLength == 0 {
+		return fmt.Errorf("%w: file '%v' record '%v' has zero record length", ErrOutOfRange, r.FileNumber, r.RecordNumber)
+	}
+	if len(r.Data) != int(r.RecordLength)*2 {
+		return fmt.Errorf("%w: file '%v' record '%v' has '%v' bytes of data, record length '%v' needs '%v' bytes", ErrOutOfRange, r.FileNumber, r.RecordNumber, len(r.Data), r.RecordLength, int(r.RecordLength)*2)
+	}
+	return nil
+}
+
+// NewFileRecordWrite 由寄存器值构造写文件记录的子请求,记录长度和数据按data自动计算
+// 记录长度超过uint16范围时由Validate/EncodeWriteFileRecord报告错误
+func NewFileRecordWrite(fileNumber uint16, startRecord uint16, data []uint16) FileRecord {
+	b := make([]byte, len(data)*2)
+	for i, v := range data {
+		binary.BigEndian.PutUint16(b[i*2:], v)
+	}
+	return FileRecord{
+		FileNumber:   fileNumber,
+		RecordNumber: startRecord,
+		RecordLength: uint16(len(data)),
+		Data:         b,
+	}
+}
+
+// NewFileRecordWrites 由同一文件中记录号->寄存器值的映射构造子请求,按记录号升序排列
+func NewFileRecordWrites(fileNumber uint16, records map[uint16][]uint16) []FileRecord {
+	numbers := make([]int, 0, len(records))
+	for n := range records {
+		numbers = append(numbers, int(n))
+	}
+	sort.Ints(numbers)
+	result := make([]FileRecord, len(numbers))
+	for i, n := range numbers {
+		result[i] = NewFileRecordWrite(fileNumber, uint16(n), records[uint16(n)])
+	}
+	return result
+}
+
+// EncodeWriteFileRecord 校验并编码写文件记录(0x15)请求的数据部分(功能码之后)
+// 字节数(1) + N*(参考类型(1) + 文件号(2) + 记录号(2) + 记录长度(2) + 记录数据(记录长度*2))
+func EncodeWriteFileRecord(records []FileRecord) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no file records to write", ErrOutOfRange)
+	}
+	size := 0
+	for _, r := range records {
+		if err := r.Validate(); err != nil {
+			return nil, err
+		}
+		size += 7 + len(r.Data)
+	}
+	if size > FILE_RECORD_MAX_DATA_BYTES {
+		return nil, fmt.Errorf("%w: file record request of '%v' bytes exceeds '%v' bytes", ErrOutOfRange, size, FILE_RECORD_MAX_DATA_BYTES)
+	}
+	data := make([]byte, 1, 1+size)
+	data[0] = byte(size)
+	for _, r := range records {
+		var header [7]byte
+		header[0] = FILE_RECORD_REFERENCE_TYPE
+		binary.BigEndian.PutUint16(header[1:], r.FileNumber)
+		binary.BigEndian.PutUint16(header[3:], r.RecordNumber)
+		binary.BigEndian.PutUint16(header[5:], r.RecordLength)
+		data = append(data, header[:]...)
+		data = append(data, r.Data...)
+	}
+	return data, nil
+}
+
+// DecodeWriteFileRecord 解码写文件记录(0x15)请求或响应的数据部分(功能码之后),正常响应原样返回请求
+// 字节数与实际长度不一致、参考类型不为6、子请求截断或超出剩余长度、文件号或记录号超出范围时返回ErrInvalidResponse
+func DecodeWriteFileRecord(data []byte) ([]FileRecord, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("%w: empty file record response", ErrInvalidResponse)
+	}
+	if int(data[0]) != len(data)-1 {
+		return nil, fmt.Errorf("%w: file record byte count '%v', payload '%v' bytes", ErrInvalidResponse, data[0], len(data)-1)
+	}
+	var records []FileRecord
+	for rest := data[1:]; len(rest) > 0; {
+		if len(rest) < 7 {
+			return nil, fmt.Errorf("%w: truncated file sub-record header, '%v' bytes remain", ErrInvalidResponse, len(rest))
+		}
+		if rest[0] != FILE_RECORD_REFERENCE_TYPE {
+			return nil, fmt.Errorf("%w: file sub-record reference type '%v', want '%v'", ErrInvalidResponse, rest[0], FILE_RECORD_REFERENCE_TYPE)
+		}
+		r := FileRecord{
+			FileNumber:   binary.BigEndian.Uint16(rest[1:]),
+			RecordNumber: binary.BigEndian.Uint16(rest[3:]),
+			RecordLength: binary.BigEndian.Uint16(rest[5:]),
+		}
+		n := 7 + int(r.RecordLength)*2
+		if len(rest) < n {
+			return nil, fmt.Errorf("%w: file '%v' record '%v' length '%v' needs '%v' bytes, '%v' remain", ErrInvalidResponse, r.FileNumber, r.RecordNumber, r.RecordLength, n-7, len(rest)-7)
+		}
+		r.Data = append([]byte(nil), rest[7:n]...)
+		if err := r.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+		}
+		records = append(records, r)
+		rest = rest[n:]
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no file sub-records", ErrInvalidResponse)
+	}
+	return records, nil
+}
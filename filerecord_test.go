@@ -0,0 +1,80 @@
+package modbus_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+func TestWriteFileRecordRoundTrip(t *testing.T) {
+	records := []modbus.FileRecord{
+		modbus.NewFileRecordWrite(4, 7, []uint16{0x06AF, 0x04BE, 0x100D}),
+		modbus.NewFileRecordWrite(3, 0x270F, []uint16{0x1234}),
+	}
+	data, err := modbus.EncodeWriteFileRecord(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 规范示例: 文件4记录7,3个寄存器
+	want := []byte{0x06, 0x00, 0x04, 0x00, 0x07, 0x00, 0x03, 0x06, 0xAF, 0x04, 0xBE, 0x10, 0x0D}
+	if !reflect.DeepEqual(data[1:1+len(want)], want) || int(data[0]) != len(data)-1 {
+		t.Fatalf("encoded % X", data)
+	}
+	got, err := modbus.DecodeWriteFileRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Fatalf("decoded %+v, want %+v", got, records)
+	}
+}
+
+func TestDecodeWriteFileRecordInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"no sub-records", []byte{0x00}},
+		{"byte count too large", []byte{0x0A, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34}},
+		{"byte count too small", []byte{0x08, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34}},
+		{"bad reference type", []byte{0x09, 0x05, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34}},
+		{"record number out of range", []byte{0x09, 0x06, 0x00, 0x01, 0x27, 0x10, 0x00, 0x01, 0x12, 0x34}},
+		{"zero file number", []byte{0x09, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34}},
+		{"zero record length", []byte{0x07, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{"truncated header", []byte{0x0C, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34, 0x06, 0x00, 0x01}},
+		{"record data truncated", []byte{0x09, 0x06, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x12, 0x34}},
+		{"record length overlong", []byte{0x09, 0x06, 0x00, 0x01, 0x00, 0x00, 0xFF, 0xFF, 0x12, 0x34}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := modbus.DecodeWriteFileRecord(tt.data)
+			if !errors.Is(err, modbus.ErrInvalidResponse) {
+				t.Fatalf("got %+v, %v, want ErrInvalidResponse", records, err)
+			}
+		})
+	}
+}
+
+func TestEncodeWriteFileRecordInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []modbus.FileRecord
+	}{
+		{"no records", nil},
+		{"zero file number", []modbus.FileRecord{modbus.NewFileRecordWrite(0, 0, []uint16{1})}},
+		{"record number out of range", []modbus.FileRecord{modbus.NewFileRecordWrite(1, 0x2710, []uint16{1})}},
+		{"zero record length", []modbus.FileRecord{modbus.NewFileRecordWrite(1, 0, nil)}},
+		{"data length mismatch", []modbus.FileRecord{{FileNumber: 1, RecordLength: 2, Data: []byte{0x12, 0x34}}}},
+		{"request too long", []modbus.FileRecord{modbus.NewFileRecordWrite(1, 0, make([]uint16, 123))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := modbus.EncodeWriteFileRecord(tt.records); !errors.Is(err, modbus.ErrOutOfRange) {
+				t.Fatalf("got %v, want ErrOutOfRange", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 设备识别(0x2B/0x0E)基本对象ID
+const (
+	OBJECT_ID_VENDOR_NAME          byte = 0 // 0(0x00) 厂商名称
+	OBJECT_ID_PRODUCT_CODE         byte = 1 // 1(0x01) 产品代码
+	OBJECT_ID_MAJOR_MINOR_REVISION byte = 2 // 2(0x02) 主次版本号
+)
+
+// 设备识别信息
+type DeviceIdentity struct {
+	VendorName         string
+	ProductCode        string
+	MajorMinorRevision string
+	// 基本对象(0x00-0x02)以外的对象,键为对象ID
+	Extended map[byte]string
+}
+
+// NewDeviceIdentity 将读设备识别得到的原始对象(对象ID->对象值)转换为DeviceIdentity
+// 对象值按UTF-8/ASCII字符串解码
+func NewDeviceIdentity(objects map[byte][]byte) *DeviceIdentity {
+	id := &DeviceIdentity{}
+	for objectID, value := range objects {
+		switch objectID {
+		case OBJECT_ID_VENDOR_NAME:
+			id.VendorName = string(value)
+		case OBJECT_ID_PRODUCT_CODE:
+			id.ProductCode = string(value)
+		case OBJECT_ID_MAJOR_MINOR_REVISION:
+			id.MajorMinorRevision = string(value)
+		default:
+			if id.Extended == nil {
+				id.Extended = make(map[byte]string)
+			}
+			id.Extended[objectID] = string(value)
+		}
+	}
+	return id
+}
+
+// 封装接口传输(0x2B)
+const (
+	ENCAPSULATED_INTERFACE_TRANSPORT byte = 43 // 43(0x2B)
+	MEI_READ_DEVICE_IDENTIFICATION   byte = 14 // 14(0x0E) 读设备识别
+)
+
+// 读设备识别码(ReadDevId code),同时也是设备声明的一致性等级(低7位)
+const (
+	ReadDeviceIDBasic    byte = 1 // 1(0x01) 基本对象流式读取
+	ReadDeviceIDRegular  byte = 2 // 2(0x02) 常规对象流式读取
+	ReadDeviceIDExtended byte = 3 // 3(0x03) 扩展对象流式读取
+	ReadDeviceIDSpecific byte = 4 // 4(0x04) 单个对象读取
+)
+
+// 一致性等级中表示支持单个对象读取的标志位
+const CONFORMITY_INDIVIDUAL_ACCESS byte = 0x80
+
+// 设备声明的一致性等级低于请求的读设备识别码
+var ErrConformityLevel = errors.New("modbus: conformity level lower than requested")
+
+// 读设备识别(0x2B/0x0E)响应
+type DeviceIdentification struct {
+	ReadCode        byte
+	ConformityLevel byte
+	MoreFollows     bool
+	NextObjectID    byte
+	// 对象ID->对象值
+	Objects map[byte][]byte
+}
+
+// ParseDeviceIdentification 解析读设备识别(0x2B/0x0E)的响应数据(功能码之后的部分)
+// readCode: 请求使用的读设备识别码[ReadDeviceIDBasic-ReadDeviceIDSpecific]
+// 设备声明的一致性等级低于readCode时返回ErrConformityLevel;
+// 请求单个对象读取时要求设备声明支持单个对象读取
+func ParseDeviceIdentification(readCode byte, data []byte) (*DeviceIdentification, error) {
+	if readCode < ReadDeviceIDBasic || readCode > ReadDeviceIDSpecific {
+		return nil, fmt.Errorf("%w: read device id code '%v'", ErrOutOfRange, readCode)
+	}
+	// MEI类型(1) + 读设备识别码(1) + 一致性等级(1) + 后续标志(1) + 下一对象ID(1) + 对象数量(1)
+	if len(data) < 6 {
+		return nil, fmt.Errorf("%w: device identification response of '%v' bytes", ErrInvalidResponse, len(data))
+	}
+	if data[0] != MEI_READ_DEVICE_IDENTIFICATION {
+		return nil, fmt.Errorf("%w: MEI type '0x%02X', expected '0x%02X'", ErrInvalidResponse, data[0], MEI_READ_DEVICE_IDENTIFICATION)
+	}
+	if data[1] != readCode {
+		return nil, fmt.Errorf("%w: read device id code '%v', expected '%v'", ErrInvalidResponse, data[1], readCode)
+	}
+	id := &DeviceIdentification{
+		ReadCode:        data[1],
+		ConformityLevel: data[2],
+		MoreFollows:     data[3] == 0xFF,
+		NextObjectID:    data[4],
+		Objects:         make(map[byte][]byte, data[5]),
+	}
+	level := id.ConformityLevel &^ CONFORMITY_INDIVIDUAL_ACCESS
+	if readCode == ReadDeviceIDSpecific {
+		if id.ConformityLevel&CONFORMITY_INDIVIDUAL_ACCESS == 0 {
+			return nil, fmt.Errorf("%w: device reports '0x%02X' without individual access", ErrConformityLevel, id.ConformityLevel)
+		}
+	} else if level < readCode {
+		return nil, fmt.Errorf("%w: device reports '0x%02X', requested '%v'", ErrConformityLevel, id.ConformityLevel, readCode)
+	}
+	rest := data[6:]
+	for i := 0; i < int(data[5]); i++ {
+		if len(rest) < 2 || len(rest) < 2+int(rest[1]) {
+			return nil, fmt.Errorf("%w: object %v of %v is truncated", ErrInvalidResponse, i+1, data[5])
+		}
+		n := int(rest[1])
+		value := make([]byte, n)
+		copy(value, rest[2:2+n])
+		id.Objects[rest[0]] = value
+		rest = rest[2+n:]
+	}
+	return id, nil
+}
+
+// ParseDeviceIdentificationObject 解析单个对象读取(ReadDeviceIDSpecific)的响应数据,返回objectID对应的对象值
+// 常用于读取厂商私有对象(对象ID>=0x80);响应中不是恰好包含所请求的一个对象时返回错误
+func ParseDeviceIdentificationObject(objectID byte, data []byte) ([]byte, error) {
+	id, err := ParseDeviceIdentification(ReadDeviceIDSpecific, data)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := id.Objects[objectID]
+	if !ok {
+		return nil, fmt.Errorf("%w: object '0x%02X' not in response", ErrResponseMismatch, objectID)
+	}
+	if len(id.Objects) != 1 {
+		return nil, fmt.Errorf("%w: '%v' objects in a specific object response", ErrInvalidResponse, len(id.Objects))
+	}
+	return value, nil
+}
@@ -0,0 +1,42 @@
+package modbus_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+func TestParseDeviceIdentificationObject(t *testing.T) {
+	// MEI类型 读设备识别码 一致性等级(0x83,支持单个对象读取) 后续标志 下一对象ID 对象数量 对象ID 长度 值
+	data := []byte{0x0E, 0x04, 0x83, 0x00, 0x00, 0x01, 0x81, 0x03, 'A', 'B', 'C'}
+	value, err := modbus.ParseDeviceIdentificationObject(0x81, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(value, []byte("ABC")) {
+		t.Fatalf("got %q, want %q", value, "ABC")
+	}
+}
+
+func TestParseDeviceIdentificationObjectErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"other object", []byte{0x0E, 0x04, 0x83, 0x00, 0x00, 0x01, 0x82, 0x01, 'A'}, modbus.ErrResponseMismatch},
+		{"two objects", []byte{0x0E, 0x04, 0x83, 0x00, 0x00, 0x02, 0x81, 0x01, 'A', 0x82, 0x01, 'B'}, modbus.ErrInvalidResponse},
+		{"no individual access", []byte{0x0E, 0x04, 0x03, 0x00, 0x00, 0x01, 0x81, 0x01, 'A'}, modbus.ErrConformityLevel},
+		{"truncated object", []byte{0x0E, 0x04, 0x83, 0x00, 0x00, 0x01, 0x81, 0x03, 'A'}, modbus.ErrInvalidResponse},
+		{"wrong read code", []byte{0x0E, 0x01, 0x83, 0x00, 0x00, 0x01, 0x81, 0x01, 'A'}, modbus.ErrInvalidResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := modbus.ParseDeviceIdentificationObject(0x81, tt.data); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// 布局中的一个字段
+type Field struct {
+	Type  DataType
+	Order ByteOrder
+}
+
+// Layout 连续寄存器块中依次排列的字段,用于一次读取并解码结构化记录
+type Layout []Field
+
+// Registers 布局占用的寄存器总数
+func (l Layout) Registers() int {
+	n := 0
+	for _, f := range l {
+		n += int(f.Type.Registers())
+	}
+	return n
+}
+
+// ReadLayout 从address开始读取布局所需的寄存器块,并按各字段的类型和字节序依次解码
+// 布局为空、包含未知类型或超过单帧读取上限时在发送请求前返回错误
+func ReadLayout(ctx context.Context, s Slaver, address uint16, layout Layout) ([]any, error) {
+	for i, f := range layout {
+		if f.Type.Registers() == 0 {
+			return nil, fmt.Errorf("modbus: layout field %v has unknown data type '%v'", i, f.Type)
+		}
+	}
+	n := layout.Registers()
+	if n == 0 || n > int(MAX_READ_REGISTERS) {
+		return nil, fmt.Errorf("%w: layout of '%v' registers must be between '1' and '%v'", ErrOutOfRange, n, MAX_READ_REGISTERS)
+	}
+	data, err := Poll(ctx, s, PollRequest{Code: READ_HOLDING_REGISTERS, Address: address, Quantity: uint16(n)})
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(layout))
+	offset := 0
+	for i, f := range layout {
+		if values[i], err = f.Type.Decode(data[offset:], f.Order); err != nil {
+			return nil, err
+		}
+		offset += int(f.Type.Registers()) * 2
+	}
+	return values, nil
+}
@@ -0,0 +1,50 @@
+package modbus
+
+import "encoding/binary"
+
+// ExpectedResponseLength 根据请求功能码和请求数据计算正常响应的数据长度
+// 返回值为响应PDU中功能码之后的数据字节数,不含功能码及ADU的头/校验部分
+// 对于长度需要由响应中的字节数字段决定的功能码(如0x0C)或请求数据不完整时ok为false
+// 异常响应的数据长度恒为1字节(异常码),不在此计算范围内
+func ExpectedResponseLength(code byte, requestData []byte) (n int, ok bool) {
+	switch code {
+	case READ_COILS, READ_DISCRETE_INPUTS:
+		// 字节数(1) + 线圈状态(N)
+		if len(requestData) < 4 {
+			return 0, false
+		}
+		quantity := int(binary.BigEndian.Uint16(requestData[2:]))
+		return 1 + (quantity+7)/8, true
+	case READ_HOLDING_REGISTERS, READ_INPUT_REGISTERS:
+		// 字节数(1) + 寄存器值(N*2)
+		if len(requestData) < 4 {
+			return 0, false
+		}
+		quantity := int(binary.BigEndian.Uint16(requestData[2:]))
+		return 1 + quantity*2, true
+	case READ_WRITE_MULTIPLE_REGISTERS:
+		// 字节数(1) + 读寄存器值(N*2)
+		if len(requestData) < 4 {
+			return 0, false
+		}
+		quantity := int(binary.BigEndian.Uint16(requestData[2:]))
+		return 1 + quantity*2, true
+	case WRITE_SINGLE_COIL, WRITE_SINGLE_REGISTER, WRITE_MULTIPLE_COILS, WRITE_MULTIPLE_REGISTERS:
+		// 地址(2) + 值/数量(2)
+		return 4, true
+	case READ_EXCEPTION_STATUS:
+		// 异常状态(1)
+		return 1, true
+	case GET_COMM_EVENT_COUNTER:
+		// 状态字(2) + 事件计数(2)
+		return 4, true
+	case DIAGNOSTICS:
+		// 标准子功能码的响应与请求等长(子功能码(2) + 数据(N*2))
+		// 子功能码0x0004(强制只听模式)无响应
+		if len(requestData) < 2 || binary.BigEndian.Uint16(requestData) == 0x0004 {
+			return 0, false
+		}
+		return len(requestData), true
+	}
+	return 0, false
+}
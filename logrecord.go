@@ -0,0 +1,148 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FunctionName 返回功能码的名称,异常响应的功能码(带0x80标志位)返回对应请求功能码的名称
+func FunctionName(code byte) string {
+	switch code &^ EXCEPTION_FLAG {
+	case READ_COILS:
+		return "ReadCoils"
+	case READ_DISCRETE_INPUTS:
+		return "ReadDiscreteInputs"
+	case READ_HOLDING_REGISTERS:
+		return "ReadHoldingRegisters"
+	case READ_INPUT_REGISTERS:
+		return "ReadInputRegisters"
+	case WRITE_SINGLE_COIL:
+		return "WriteSingleCoil"
+	case WRITE_SINGLE_REGISTER:
+		return "WriteSingleRegister"
+	case READ_EXCEPTION_STATUS:
+		return "ReadExceptionStatus"
+	case DIAGNOSTICS:
+		return "Diagnostics"
+	case GET_COMM_EVENT_COUNTER:
+		return "GetCommEventCounter"
+	case GET_COMM_EVENT_LOG:
+		return "GetCommEventLog"
+	case WRITE_MULTIPLE_COILS:
+		return "WriteMultipleCoils"
+	case WRITE_MULTIPLE_REGISTERS:
+		return "WriteMultipleRegisters"
+	case REPORT_SERVER_ID:
+		return "ReportServerID"
+	case WRITE_FILE_RECORD:
+		return "WriteFileRecord"
+	case READ_WRITE_MULTIPLE_REGISTERS:
+		return "ReadWriteMultipleRegisters"
+	case ENCAPSULATED_INTERFACE_TRANSPORT:
+		return "EncapsulatedInterfaceTransport"
+	}
+	return fmt.Sprintf("Function(0x%02X)", code&^EXCEPTION_FLAG)
+}
+
+// 交互的数据方向
+const (
+	DIRECTION_READ       = "read"       // 从设备读取(0x01-0x04)
+	DIRECTION_WRITE      = "write"      // 向设备写入(0x05,0x06,0x0F,0x10,0x15)
+	DIRECTION_READ_WRITE = "read-write" // 同时读写(0x17)
+)
+
+// DirectionOf 返回功能码的数据方向,诊断等不涉及数据区读写的功能码返回空字符串
+func DirectionOf(code byte) string {
+	switch code &^ EXCEPTION_FLAG {
+	case READ_COILS, READ_DISCRETE_INPUTS, READ_HOLDING_REGISTERS, READ_INPUT_REGISTERS:
+		return DIRECTION_READ
+	case WRITE_SINGLE_COIL, WRITE_SINGLE_REGISTER, WRITE_MULTIPLE_COILS, WRITE_MULTIPLE_REGISTERS, WRITE_FILE_RECORD:
+		return DIRECTION_WRITE
+	case READ_WRITE_MULTIPLE_REGISTERS:
+		return DIRECTION_READ_WRITE
+	}
+	return ""
+}
+
+// 一次请求/响应交互的结构化记录,适用于审计日志
+type LogRecord struct {
+	Time         time.Time
+	Duration     time.Duration
+	FunctionCode byte
+	FunctionName string
+	// 数据方向,见DirectionOf
+	Direction string
+	// 仅对带起始地址和数量的功能码(0x01-0x04,0x0F,0x10,0x17)有效
+	Address  uint16
+	Quantity uint16
+	// 结果摘要,如"ok (5 bytes)"或异常描述
+	Result string
+	Err    error
+}
+
+type logTransporter struct {
+	Transporter
+	packager Packager
+	fn       func(LogRecord)
+}
+
+// LogTransactions 包装传输层,每次交互完成后以LogRecord调用fn
+// 功能码、地址和数量由packager解码请求ADU得到;fn在Send的调用goroutine中同步执行
+// packager为nil时不解码,记录中只有时间、耗时和错误;fn为nil时不记录
+func LogTransactions(packager Packager, transporter Transporter, fn func(LogRecord)) Transporter {
+	return &logTransporter{Transporter: transporter, packager: packager, fn: fn}
+}
+
+func (t *logTransporter) Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) (readu []byte, err error) {
+	if t.fn == nil {
+		return t.Transporter.Send(ctx, adu, waitTimes, timeout)
+	}
+	start := clk.Now()
+	readu, err = t.Transporter.Send(ctx, adu, waitTimes, timeout)
+	record := LogRecord{Time: start, Duration: clk.Now().Sub(start), Err: err}
+	if t.packager == nil {
+		record.Result = t.summarize(ctx, readu, err)
+		t.fn(record)
+		return readu, err
+	}
+	if pdu, derr := t.packager.Decode(ctx, adu); derr == nil && pdu != nil {
+		record.FunctionCode = pdu.Code
+		record.FunctionName = FunctionName(pdu.Code)
+		record.Direction = DirectionOf(pdu.Code)
+		switch pdu.Code {
+		case READ_COILS, READ_DISCRETE_INPUTS, READ_HOLDING_REGISTERS, READ_INPUT_REGISTERS,
+			WRITE_MULTIPLE_COILS, WRITE_MULTIPLE_REGISTERS, READ_WRITE_MULTIPLE_REGISTERS:
+			address, ok1 := pdu.Uint16At(0)
+			quantity, ok2 := pdu.Uint16At(2)
+			if ok1 && ok2 {
+				record.Address, record.Quantity = address, quantity
+			}
+		case WRITE_SINGLE_COIL, WRITE_SINGLE_REGISTER:
+			if address, ok := pdu.Uint16At(0); ok {
+				record.Address, record.Quantity = address, 1
+			}
+		}
+	}
+	record.Result = t.summarize(ctx, readu, err)
+	t.fn(record)
+	return readu, err
+}
+
+// summarize 生成响应的结果摘要
+func (t *logTransporter) summarize(ctx context.Context, readu []byte, err error) string {
+	if err != nil {
+		return "error"
+	}
+	if t.packager == nil {
+		return fmt.Sprintf("ok (%v bytes)", len(readu))
+	}
+	pdu, derr := t.packager.Decode(ctx, readu)
+	if derr != nil || pdu == nil {
+		return fmt.Sprintf("undecodable response (%v bytes)", len(readu))
+	}
+	if e := ParseException(pdu); e != nil {
+		return e.Error()
+	}
+	return fmt.Sprintf("ok (%v bytes)", len(pdu.Data))
+}
@@ -0,0 +1,73 @@
+package modbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestLogTransactions(t *testing.T) {
+	// 测试用的ADU即为功能码 + 数据
+	packager := &modbustest.MockPackager{DecodeFunc: func(ctx context.Context, adu []byte) (*modbus.ProtocolDataUnit, error) {
+		return &modbus.ProtocolDataUnit{Code: adu[0], Data: adu[1:]}, nil
+	}}
+	transporter := &modbustest.MockTransporter{SendFunc: func(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error) {
+		if adu[0] == modbus.WRITE_SINGLE_REGISTER {
+			return []byte{adu[0] | modbus.EXCEPTION_FLAG, modbus.ILLEGAL_DATA_VALUE}, nil
+		}
+		return []byte{adu[0], 0x02, 0x00, 0x2A}, nil
+	}}
+	var records []modbus.LogRecord
+	tr := modbus.LogTransactions(packager, transporter, func(r modbus.LogRecord) { records = append(records, r) })
+
+	tr.Send(context.Background(), []byte{modbus.READ_HOLDING_REGISTERS, 0x00, 0x10, 0x00, 0x01}, 0, 0)
+	tr.Send(context.Background(), []byte{modbus.WRITE_SINGLE_REGISTER, 0x00, 0x20, 0xFF, 0xFF}, 0, 0)
+	tr.Send(context.Background(), []byte{modbus.READ_HOLDING_REGISTERS, 0x00}, 0, 0)
+
+	want := []modbus.LogRecord{
+		{FunctionCode: modbus.READ_HOLDING_REGISTERS, FunctionName: "ReadHoldingRegisters", Direction: modbus.DIRECTION_READ, Address: 0x10, Quantity: 1, Result: "ok (3 bytes)"},
+		{FunctionCode: modbus.WRITE_SINGLE_REGISTER, FunctionName: "WriteSingleRegister", Direction: modbus.DIRECTION_WRITE, Address: 0x20, Quantity: 1, Result: "modbus: exception '3' (illegal data value), function '6'"},
+		// 请求数据不完整时不填写地址和数量
+		{FunctionCode: modbus.READ_HOLDING_REGISTERS, FunctionName: "ReadHoldingRegisters", Direction: modbus.DIRECTION_READ, Result: "ok (3 bytes)"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %v records, want %v", len(records), len(want))
+	}
+	for i, r := range records {
+		r.Time, r.Duration = time.Time{}, 0
+		if r != want[i] {
+			t.Errorf("record %v = %+v, want %+v", i, r, want[i])
+		}
+	}
+}
+
+func TestLogTransactionsNilPackagerAndFn(t *testing.T) {
+	transporter := &modbustest.MockTransporter{SendFunc: func(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error) {
+		return []byte{adu[0], 0x02, 0x00, 0x2A}, nil
+	}}
+	adu := []byte{modbus.READ_HOLDING_REGISTERS, 0x00, 0x10, 0x00, 0x01}
+
+	// packager为nil时不解码,只记录时间、耗时和结果
+	var records []modbus.LogRecord
+	tr := modbus.LogTransactions(nil, transporter, func(r modbus.LogRecord) { records = append(records, r) })
+	if _, err := tr.Send(context.Background(), adu, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("got %v records, want 1", len(records))
+	}
+	r := records[0]
+	r.Time, r.Duration = time.Time{}, 0
+	if want := (modbus.LogRecord{Result: "ok (4 bytes)"}); r != want {
+		t.Fatalf("record = %+v, want %+v", r, want)
+	}
+
+	// fn为nil时直接透传
+	readu, err := modbus.LogTransactions(nil, transporter, nil).Send(context.Background(), adu, 0, 0)
+	if err != nil || len(readu) != 4 {
+		t.Fatalf("got % X, %v", readu, err)
+	}
+}
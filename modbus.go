@@ -2,25 +2,32 @@ package modbus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
 // 功能码常量 bit access
 const (
-	READ_COILS           byte = 1 // 1(0x01)
-	READ_DISCRETE_INPUTS byte = 2 // 2(0x02)
-	WRITE_SINGLE_COIL    byte = 5 // 5(0x05)
+	READ_COILS           byte = 1  // 1(0x01)
+	READ_DISCRETE_INPUTS byte = 2  // 2(0x02)
+	WRITE_SINGLE_COIL    byte = 5  // 5(0x05)
+	WRITE_MULTIPLE_COILS byte = 15 // 15(0x0F)
 )
 
 // 功能码常量 16-bit access
 const (
-	READ_HOLDING_REGISTERS byte = 3 // 3(0x03)
-	READ_INPUT_REGISTERS   byte = 4 // 4(0x04)
-	WRITE_SINGLE_REGISTER  byte = 6 // 6(0x06)
+	READ_HOLDING_REGISTERS        byte = 3  // 3(0x03)
+	READ_INPUT_REGISTERS          byte = 4  // 4(0x04)
+	WRITE_SINGLE_REGISTER         byte = 6  // 6(0x06)
+	WRITE_MULTIPLE_REGISTERS      byte = 16 // 16(0x10)
+	READ_WRITE_MULTIPLE_REGISTERS byte = 23 // 23(0x17)
 )
 const (
-	READ_EXCEPTION_STATUS byte = 7 // 7(0x07)
+	READ_EXCEPTION_STATUS  byte = 7  // 7(0x07)
+	DIAGNOSTICS            byte = 8  // 8(0x08)
+	GET_COMM_EVENT_COUNTER byte = 11 // 11(0x0B)
+	GET_COMM_EVENT_LOG     byte = 12 // 12(0x0C)
 )
 
 // modbus function
@@ -79,14 +86,16 @@ type Slaver interface {
 	WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error)
 	// 在远程设备中一个连续(1-123)寄存器块写入数据
 	// 功能码: 1字节,16 (0x10)
-	// address: 2字节,寄存器起始地址,寻址范围[0x0001-0x007B]
-	// quantity: 2字节,寄存器数量[0x0001-0x07B0]
+	// address: 2字节,寄存器起始地址,寻址范围[0x0000-0xFFFF]
+	// quantity: 2字节,寄存器数量[0x0001-0x007B]
 	// value: N*2字节,数据
 	WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error)
 }
 
 // ProtocolDataUnit (PDU) is independent of underlying communication layers.
 type ProtocolDataUnit struct {
+	// Code is the function code as it appears on the wire; for exception
+	// responses Packager.Decode keeps the 0x80 bit (request code|0x80).
 	Code   byte
 	Data   []byte
 	Refin  bool
@@ -95,6 +104,12 @@ type ProtocolDataUnit struct {
 
 // Packager specifies the communication layer.
 //
+// Verify checks a response ADU against the request ADU that produced it. It
+// returns nil for a normal response, the *Error for a well-formed exception
+// response to the request's function code, and an error wrapping
+// ErrResponseMismatch when the function codes are inconsistent. Packagers can
+// share this logic through VerifyResponse.
+//
 //go:generate mockery -name Packager
 type Packager interface {
 	Encode(ctx context.Context, pdu *ProtocolDataUnit) (adu []byte, err error)
@@ -104,11 +119,23 @@ type Packager interface {
 
 // Transporter specifies the transport layer.
 //
+// When no complete response arrives within the timeout, Send should return
+// ErrNoResponse if zero bytes were received and ErrPartialResponse if some
+// bytes arrived but not a full frame.
+//
 //go:generate mockery -name Transporter
 type Transporter interface {
 	Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) (readu []byte, err error)
 }
 
+// 超时分类
+var (
+	// 超时内未收到任何字节,通常为接线、地址或设备离线问题
+	ErrNoResponse = errors.New("modbus: no response")
+	// 超时内收到部分字节但不足一帧,通常为时序或组帧问题
+	ErrPartialResponse = errors.New("modbus: partial response")
+)
+
 // 异常状态码常量
 const (
 	ILLEGAL_FUNCTION                        byte = 1  // 1(0x01) 非法的功能码
@@ -129,6 +156,11 @@ type Error struct {
 }
 
 func (e *Error) Error() string {
+	return e.message("unknown")
+}
+
+// message 生成异常信息,unknown为标准异常码以外的异常码的描述
+func (e *Error) message(unknown string) string {
 	var msg string
 	switch e.ExceptionCode {
 	case ILLEGAL_FUNCTION: // 1(0x01) 非法的功能码
@@ -150,7 +182,19 @@ func (e *Error) Error() string {
 	case GATEWAY_TARGET_DEVICE_FAILED_TO_RESPOND: // 11(0x0B) 网关目标设备响应失败
 		msg = "gateway target device failed to respond"
 	default:
-		msg = "unknown"
+		msg = unknown
 	}
 	return fmt.Sprintf("modbus: exception '%v' (%s), function '%v'", e.ExceptionCode, msg, e.FunctionCode)
 }
+
+// IsTransient 异常是否为暂时性的,可在稍后重试
+// ACKNOWLEDGE(0x05)表示请求已接受仍在处理,SERVER_DEVICE_BUSY(0x06)表示设备正忙,二者稍后重试即可能成功;
+// ILLEGAL_FUNCTION(0x01)、ILLEGAL_DATA_ADDRESS(0x02)、ILLEGAL_DATA_VALUE(0x03)等由请求本身导致,
+// 原样重试结果不会改变,其余异常码同样按非暂时性处理
+func (e *Error) IsTransient() bool {
+	switch e.ExceptionCode {
+	case ACKNOWLEDGE, SERVER_DEVICE_BUSY:
+		return true
+	}
+	return false
+}
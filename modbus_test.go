@@ -0,0 +1,31 @@
+package modbus_test
+
+import (
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+func TestErrorIsTransient(t *testing.T) {
+	tests := []struct {
+		code      byte
+		transient bool
+	}{
+		{modbus.ILLEGAL_FUNCTION, false},
+		{modbus.ILLEGAL_DATA_ADDRESS, false},
+		{modbus.ILLEGAL_DATA_VALUE, false},
+		{modbus.SERVER_DEVICE_FAILURE, false},
+		{modbus.ACKNOWLEDGE, true},
+		{modbus.SERVER_DEVICE_BUSY, true},
+		{modbus.MEMORY_PARITY_ERROR, false},
+		{modbus.GATEWAY_PATH_UNAVAILABLE, false},
+		{modbus.GATEWAY_TARGET_DEVICE_FAILED_TO_RESPOND, false},
+		{0x80, false},
+	}
+	for _, tt := range tests {
+		e := &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: tt.code}
+		if got := e.IsTransient(); got != tt.transient {
+			t.Errorf("exception '%v' IsTransient() = %v, want %v", tt.code, got, tt.transient)
+		}
+	}
+}
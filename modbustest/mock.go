@@ -0,0 +1,161 @@
+// Package modbustest 提供modbus接口的测试替身,便于下游在测试中注入假实现
+package modbustest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kokutas/modbus"
+)
+
+var (
+	_ modbus.Slaver      = (*MockSlaver)(nil)
+	_ modbus.Packager    = (*MockPackager)(nil)
+	_ modbus.Transporter = (*MockTransporter)(nil)
+)
+
+// notImplemented 未设置对应函数字段时返回的错误
+func notImplemented(method string) error {
+	return fmt.Errorf("modbustest: %s not implemented", method)
+}
+
+// MockSlaver 以函数字段实现modbus.Slaver,未设置的方法返回错误
+type MockSlaver struct {
+	ReadCoilsFunc              func(ctx context.Context, address, quantity uint16) ([]byte, error)
+	ReadDiscreteInputsFunc     func(ctx context.Context, address, quantity uint16) ([]byte, error)
+	ReadHoldingRegistersFunc   func(ctx context.Context, address, quantity uint16) ([]byte, error)
+	ReadInputRegistersFunc     func(ctx context.Context, address, quantity uint16) ([]byte, error)
+	WriteSingleCoilFunc        func(ctx context.Context, address, value uint16) ([]byte, error)
+	WriteSingleRegisterFunc    func(ctx context.Context, address, value uint16) ([]byte, error)
+	ReadExceptionStatusFunc    func(ctx context.Context) ([]byte, error)
+	DiagnosticsFunc            func(ctx context.Context, subFunc uint16, value []byte) ([]byte, error)
+	GetCommEventCounterFunc    func(ctx context.Context) ([]byte, error)
+	GetCommEventLogFunc        func(ctx context.Context) ([]byte, error)
+	WriteMultipleCoilsFunc     func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error)
+	WriteMultipleregistersFunc func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error)
+}
+
+func (m *MockSlaver) ReadCoils(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	if m.ReadCoilsFunc == nil {
+		return nil, notImplemented("ReadCoils")
+	}
+	return m.ReadCoilsFunc(ctx, address, quantity)
+}
+
+func (m *MockSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	if m.ReadDiscreteInputsFunc == nil {
+		return nil, notImplemented("ReadDiscreteInputs")
+	}
+	return m.ReadDiscreteInputsFunc(ctx, address, quantity)
+}
+
+func (m *MockSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	if m.ReadHoldingRegistersFunc == nil {
+		return nil, notImplemented("ReadHoldingRegisters")
+	}
+	return m.ReadHoldingRegistersFunc(ctx, address, quantity)
+}
+
+func (m *MockSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	if m.ReadInputRegistersFunc == nil {
+		return nil, notImplemented("ReadInputRegisters")
+	}
+	return m.ReadInputRegistersFunc(ctx, address, quantity)
+}
+
+func (m *MockSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) ([]byte, error) {
+	if m.WriteSingleCoilFunc == nil {
+		return nil, notImplemented("WriteSingleCoil")
+	}
+	return m.WriteSingleCoilFunc(ctx, address, value)
+}
+
+func (m *MockSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) ([]byte, error) {
+	if m.WriteSingleRegisterFunc == nil {
+		return nil, notImplemented("WriteSingleRegister")
+	}
+	return m.WriteSingleRegisterFunc(ctx, address, value)
+}
+
+func (m *MockSlaver) ReadExceptionStatus(ctx context.Context) ([]byte, error) {
+	if m.ReadExceptionStatusFunc == nil {
+		return nil, notImplemented("ReadExceptionStatus")
+	}
+	return m.ReadExceptionStatusFunc(ctx)
+}
+
+func (m *MockSlaver) Diagnostics(ctx context.Context, subFunc uint16, value []byte) ([]byte, error) {
+	if m.DiagnosticsFunc == nil {
+		return nil, notImplemented("Diagnostics")
+	}
+	return m.DiagnosticsFunc(ctx, subFunc, value)
+}
+
+func (m *MockSlaver) GetCommEventCounter(ctx context.Context) ([]byte, error) {
+	if m.GetCommEventCounterFunc == nil {
+		return nil, notImplemented("GetCommEventCounter")
+	}
+	return m.GetCommEventCounterFunc(ctx)
+}
+
+func (m *MockSlaver) GetCommEventLog(ctx context.Context) ([]byte, error) {
+	if m.GetCommEventLogFunc == nil {
+		return nil, notImplemented("GetCommEventLog")
+	}
+	return m.GetCommEventLogFunc(ctx)
+}
+
+func (m *MockSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+	if m.WriteMultipleCoilsFunc == nil {
+		return nil, notImplemented("WriteMultipleCoils")
+	}
+	return m.WriteMultipleCoilsFunc(ctx, address, quantity, value)
+}
+
+func (m *MockSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+	if m.WriteMultipleregistersFunc == nil {
+		return nil, notImplemented("WriteMultipleregisters")
+	}
+	return m.WriteMultipleregistersFunc(ctx, address, quantity, value)
+}
+
+// MockPackager 以函数字段实现modbus.Packager,未设置的方法返回错误
+type MockPackager struct {
+	EncodeFunc func(ctx context.Context, pdu *modbus.ProtocolDataUnit) ([]byte, error)
+	DecodeFunc func(ctx context.Context, adu []byte) (*modbus.ProtocolDataUnit, error)
+	VerifyFunc func(ctx context.Context, adu []byte, readu []byte) error
+}
+
+func (m *MockPackager) Encode(ctx context.Context, pdu *modbus.ProtocolDataUnit) ([]byte, error) {
+	if m.EncodeFunc == nil {
+		return nil, notImplemented("Encode")
+	}
+	return m.EncodeFunc(ctx, pdu)
+}
+
+func (m *MockPackager) Decode(ctx context.Context, adu []byte) (*modbus.ProtocolDataUnit, error) {
+	if m.DecodeFunc == nil {
+		return nil, notImplemented("Decode")
+	}
+	return m.DecodeFunc(ctx, adu)
+}
+
+func (m *MockPackager) Verify(ctx context.Context, adu []byte, readu []byte) error {
+	if m.VerifyFunc == nil {
+		return notImplemented("Verify")
+	}
+	return m.VerifyFunc(ctx, adu, readu)
+}
+
+// MockTransporter 以函数字段实现modbus.Transporter,未设置时返回错误
+type MockTransporter struct {
+	SendFunc func(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error)
+}
+
+func (m *MockTransporter) Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error) {
+	if m.SendFunc == nil {
+		return nil, notImplemented("Send")
+	}
+	return m.SendFunc(ctx, adu, waitTimes, timeout)
+}
@@ -0,0 +1,82 @@
+package modbustest
+
+import (
+	"context"
+
+	"github.com/kokutas/modbus"
+)
+
+var _ modbus.Slaver = (*ScriptedSlaver)(nil)
+
+// 预设的响应
+type Response struct {
+	Results []byte
+	Err     error
+}
+
+// ScriptedSlaver 按功能码返回预设响应的modbus.Slaver,适用于表驱动测试
+// 未预设响应的功能码返回ILLEGAL_FUNCTION异常
+type ScriptedSlaver struct {
+	Responses map[byte]Response
+}
+
+// NewScriptedSlaver 创建按功能码返回预设响应的ScriptedSlaver
+func NewScriptedSlaver(responses map[byte]Response) *ScriptedSlaver {
+	return &ScriptedSlaver{Responses: responses}
+}
+
+func (s *ScriptedSlaver) respond(code byte) ([]byte, error) {
+	r, ok := s.Responses[code]
+	if !ok {
+		return nil, &modbus.Error{FunctionCode: code, ExceptionCode: modbus.ILLEGAL_FUNCTION}
+	}
+	return r.Results, r.Err
+}
+
+func (s *ScriptedSlaver) ReadCoils(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	return s.respond(modbus.READ_COILS)
+}
+
+func (s *ScriptedSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	return s.respond(modbus.READ_DISCRETE_INPUTS)
+}
+
+func (s *ScriptedSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	return s.respond(modbus.READ_HOLDING_REGISTERS)
+}
+
+func (s *ScriptedSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) ([]byte, error) {
+	return s.respond(modbus.READ_INPUT_REGISTERS)
+}
+
+func (s *ScriptedSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) ([]byte, error) {
+	return s.respond(modbus.WRITE_SINGLE_COIL)
+}
+
+func (s *ScriptedSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) ([]byte, error) {
+	return s.respond(modbus.WRITE_SINGLE_REGISTER)
+}
+
+func (s *ScriptedSlaver) ReadExceptionStatus(ctx context.Context) ([]byte, error) {
+	return s.respond(modbus.READ_EXCEPTION_STATUS)
+}
+
+func (s *ScriptedSlaver) Diagnostics(ctx context.Context, subFunc uint16, value []byte) ([]byte, error) {
+	return s.respond(modbus.DIAGNOSTICS)
+}
+
+func (s *ScriptedSlaver) GetCommEventCounter(ctx context.Context) ([]byte, error) {
+	return s.respond(modbus.GET_COMM_EVENT_COUNTER)
+}
+
+func (s *ScriptedSlaver) GetCommEventLog(ctx context.Context) ([]byte, error) {
+	return s.respond(modbus.GET_COMM_EVENT_LOG)
+}
+
+func (s *ScriptedSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+	return s.respond(modbus.WRITE_MULTIPLE_COILS)
+}
+
+func (s *ScriptedSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+	return s.respond(modbus.WRITE_MULTIPLE_REGISTERS)
+}
@@ -0,0 +1,87 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Byte 返回Data中第i个字节,越界时ok为false
+func (pdu *ProtocolDataUnit) Byte(i int) (b byte, ok bool) {
+	if pdu == nil || i < 0 || i >= len(pdu.Data) {
+		return 0, false
+	}
+	return pdu.Data[i], true
+}
+
+// Uint16At 返回Data中从第i个字节开始的大端16位值,越界时ok为false
+func (pdu *ProtocolDataUnit) Uint16At(i int) (v uint16, ok bool) {
+	if pdu == nil || i < 0 || i+2 > len(pdu.Data) {
+		return 0, false
+	}
+	return binary.BigEndian.Uint16(pdu.Data[i:]), true
+}
+
+// ByteCount 返回响应中首字节声明的字节数
+// 当Data为空或声明的字节数超出实际剩余数据长度时ok为false
+func (pdu *ProtocolDataUnit) ByteCount() (n int, ok bool) {
+	b, ok := pdu.Byte(0)
+	if !ok {
+		return 0, false
+	}
+	n = int(b)
+	if n > len(pdu.Data)-1 {
+		return n, false
+	}
+	return n, true
+}
+
+// 异常响应功能码的标志位: 异常响应的功能码为请求功能码|0x80
+const EXCEPTION_FLAG byte = 0x80
+
+// IsException 是否为异常响应(功能码最高位为1)
+func (pdu *ProtocolDataUnit) IsException() bool {
+	return pdu != nil && pdu.Code&EXCEPTION_FLAG != 0
+}
+
+// ExceptionCode 返回异常响应中的异常码,非异常响应或数据缺失时ok为false
+func (pdu *ProtocolDataUnit) ExceptionCode() (code byte, ok bool) {
+	if !pdu.IsException() {
+		return 0, false
+	}
+	return pdu.Byte(0)
+}
+
+// 响应与请求不匹配(功能码、从站地址或事务标识不一致)
+var ErrResponseMismatch = errors.New("modbus: response does not match request")
+
+// ParseException 将异常响应PDU解析为*Error,FunctionCode为响应中声明的功能码(去除0x80标志位)
+// 非异常响应返回nil;异常响应缺少异常码时返回ErrInvalidResponse
+func ParseException(pdu *ProtocolDataUnit) error {
+	if !pdu.IsException() {
+		return nil
+	}
+	code, ok := pdu.ExceptionCode()
+	if !ok {
+		return fmt.Errorf("%w: exception response to function '%v' has no exception code", ErrInvalidResponse, pdu.Code&^EXCEPTION_FLAG)
+	}
+	return &Error{FunctionCode: pdu.Code &^ EXCEPTION_FLAG, ExceptionCode: code}
+}
+
+// VerifyResponse 按Packager.Verify的约定校验已解码的请求与响应PDU
+// 正常响应返回nil;针对请求功能码的异常响应返回*Error;功能码不一致返回ErrResponseMismatch
+// 若响应是针对其他功能码的异常响应,返回的错误同时包装ErrResponseMismatch和声明该功能码的*Error,
+// 可分别通过errors.Is和errors.As获取
+func VerifyResponse(request, response *ProtocolDataUnit) error {
+	if response.Code == request.Code {
+		return nil
+	}
+	if response.IsException() {
+		err := ParseException(response)
+		if response.Code&^EXCEPTION_FLAG == request.Code {
+			return err
+		}
+		return fmt.Errorf("%w: exception response for function '%v' to request function '%v': %w", ErrResponseMismatch, response.Code&^EXCEPTION_FLAG, request.Code, err)
+	}
+	return fmt.Errorf("%w: response function '%v', request function '%v'", ErrResponseMismatch, response.Code, request.Code)
+}
@@ -0,0 +1,44 @@
+package modbus_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+func TestVerifyResponseMismatchedException(t *testing.T) {
+	request := &modbus.ProtocolDataUnit{Code: modbus.READ_HOLDING_REGISTERS, Data: []byte{0x00, 0x00, 0x00, 0x01}}
+	// 针对写单个寄存器(0x06)的非法数据地址异常
+	response := &modbus.ProtocolDataUnit{Code: modbus.WRITE_SINGLE_REGISTER | modbus.EXCEPTION_FLAG, Data: []byte{modbus.ILLEGAL_DATA_ADDRESS}}
+
+	err := modbus.VerifyResponse(request, response)
+	if !errors.Is(err, modbus.ErrResponseMismatch) {
+		t.Fatalf("got %v, want ErrResponseMismatch", err)
+	}
+	var e *modbus.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("%v does not wrap *Error", err)
+	}
+	if e.FunctionCode != modbus.WRITE_SINGLE_REGISTER || e.ExceptionCode != modbus.ILLEGAL_DATA_ADDRESS {
+		t.Fatalf("got %+v, want function '%v' exception '%v'", e, modbus.WRITE_SINGLE_REGISTER, modbus.ILLEGAL_DATA_ADDRESS)
+	}
+}
+
+func TestVerifyResponse(t *testing.T) {
+	request := &modbus.ProtocolDataUnit{Code: modbus.READ_HOLDING_REGISTERS}
+	if err := modbus.VerifyResponse(request, &modbus.ProtocolDataUnit{Code: modbus.READ_HOLDING_REGISTERS}); err != nil {
+		t.Fatalf("normal response: %v", err)
+	}
+
+	err := modbus.VerifyResponse(request, &modbus.ProtocolDataUnit{Code: modbus.READ_HOLDING_REGISTERS | modbus.EXCEPTION_FLAG, Data: []byte{modbus.SERVER_DEVICE_BUSY}})
+	var e *modbus.Error
+	if !errors.As(err, &e) || e.ExceptionCode != modbus.SERVER_DEVICE_BUSY || errors.Is(err, modbus.ErrResponseMismatch) {
+		t.Fatalf("matching exception: got %v", err)
+	}
+
+	err = modbus.VerifyResponse(request, &modbus.ProtocolDataUnit{Code: modbus.READ_INPUT_REGISTERS})
+	if !errors.Is(err, modbus.ErrResponseMismatch) || errors.As(err, &e) {
+		t.Fatalf("mismatched function: got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// 轮询请求,Code为读功能码(0x01-0x04)
+type PollRequest struct {
+	Code     byte
+	Address  uint16
+	Quantity uint16
+}
+
+// 轮询结果,Reading.Value为去除字节数字段后的线圈状态或寄存器值([]byte)
+type PollResult struct {
+	Request PollRequest
+	Reading
+}
+
+// 轮询目标设备及其请求
+type PollTarget struct {
+	Slaver   Slaver
+	Requests []PollRequest
+}
+
+// MultiPoller 并发轮询多个设备
+// 同一设备的请求按顺序依次发送,不同设备之间并发执行,同时进行的设备数不超过并发上限
+// 每个请求最近一次成功的读数会被保留,读取失败时以QUALITY_STALE返回该旧值
+type MultiPoller struct {
+	concurrency int
+
+	mu   sync.Mutex
+	last map[string]map[PollRequest]Reading
+}
+
+// NewMultiPoller 创建MultiPoller,concurrency为同时轮询的设备数上限,小于1时按1处理
+func NewMultiPoller(concurrency int) *MultiPoller {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &MultiPoller{concurrency: concurrency, last: make(map[string]map[PollRequest]Reading)}
+}
+
+// Poll 轮询所有目标设备,返回以设备名为键的结果,每个结果与请求一一对应
+// 上下文结束后尚未发送的请求不再发送,其结果的Err为ctx.Err()
+func (p *MultiPoller) Poll(ctx context.Context, targets map[string]PollTarget) map[string][]PollResult {
+	results := make(map[string][]PollResult, len(targets))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.concurrency)
+	for name, target := range targets {
+		wg.Add(1)
+		go func(name string, target PollTarget) {
+			defer wg.Done()
+			var rs []PollResult
+			select {
+			case sem <- struct{}{}:
+				rs = pollDevice(ctx, target)
+				<-sem
+			case <-ctx.Done():
+				rs = make([]PollResult, len(target.Requests))
+				for i, req := range target.Requests {
+					rs[i] = PollResult{Request: req, Reading: Reading{Timestamp: clk.Now(), Err: ctx.Err()}}
+				}
+			}
+			p.qualify(name, rs)
+			mu.Lock()
+			results[name] = rs
+			mu.Unlock()
+		}(name, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// pollDevice 按顺序发送单个设备的全部请求
+func pollDevice(ctx context.Context, target PollTarget) []PollResult {
+	rs := make([]PollResult, len(target.Requests))
+	for i, req := range target.Requests {
+		rs[i].Request = req
+		if err := ctx.Err(); err != nil {
+			rs[i].Timestamp, rs[i].Err = clk.Now(), err
+			continue
+		}
+		data, err := Poll(ctx, target.Slaver, req)
+		rs[i].Timestamp, rs[i].Err = clk.Now(), err
+		if err == nil {
+			rs[i].Value = data
+		}
+	}
+	return rs
+}
+
+// qualify 设置读数质量:成功的读数记为最新值,失败的读数有旧值时记为QUALITY_STALE并带回旧值
+func (p *MultiPoller) qualify(name string, rs []PollResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last := p.last[name]
+	if last == nil {
+		last = make(map[PollRequest]Reading)
+		p.last[name] = last
+	}
+	for i := range rs {
+		r := &rs[i]
+		if r.Err == nil {
+			r.Quality = QUALITY_GOOD
+			last[r.Request] = r.Reading
+			continue
+		}
+		if prev, ok := last[r.Request]; ok {
+			r.Value, r.Quality = prev.Value, QUALITY_STALE
+		} else {
+			r.Quality = QUALITY_BAD
+		}
+	}
+}
+
+// Poll 按请求的读功能码读取单个设备,返回去除字节数字段后的数据
+func Poll(ctx context.Context, s Slaver, req PollRequest) ([]byte, error) {
+	if err := ValidateQuantity(req.Code, req.Quantity); err != nil {
+		return nil, err
+	}
+	switch req.Code {
+	case READ_COILS, READ_DISCRETE_INPUTS:
+		read := s.ReadCoils
+		if req.Code == READ_DISCRETE_INPUTS {
+			read = s.ReadDiscreteInputs
+		}
+		results, err := read(ctx, req.Address, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		return coilData(results, req.Quantity)
+	case READ_HOLDING_REGISTERS, READ_INPUT_REGISTERS:
+		read := s.ReadHoldingRegisters
+		if req.Code == READ_INPUT_REGISTERS {
+			read = s.ReadInputRegisters
+		}
+		results, err := read(ctx, req.Address, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		return registerData(results, req.Quantity)
+	}
+	return nil, fmt.Errorf("%w: function '%v' is not a read function", ErrOutOfRange, req.Code)
+}
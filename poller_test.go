@@ -0,0 +1,84 @@
+package modbus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestMultiPollerQuality(t *testing.T) {
+	errDown := errors.New("device down")
+	fail := false
+	s := &modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		if fail {
+			return nil, errDown
+		}
+		return []byte{0x02, 0x12, 0x34}, nil
+	}}
+	req := modbus.PollRequest{Code: modbus.READ_HOLDING_REGISTERS, Address: 0, Quantity: 1}
+	targets := map[string]modbus.PollTarget{"plc": {Slaver: s, Requests: []modbus.PollRequest{req}}}
+	p := modbus.NewMultiPoller(1)
+
+	r := p.Poll(context.Background(), targets)["plc"][0]
+	if r.Err != nil || r.Quality != modbus.QUALITY_GOOD || !bytes.Equal(r.Value.([]byte), []byte{0x12, 0x34}) {
+		t.Fatalf("first poll = %+v, want good 12 34", r)
+	}
+
+	fail = true
+	r = p.Poll(context.Background(), targets)["plc"][0]
+	if !errors.Is(r.Err, errDown) || r.Quality != modbus.QUALITY_STALE || !bytes.Equal(r.Value.([]byte), []byte{0x12, 0x34}) {
+		t.Fatalf("poll after failure = %+v, want stale 12 34 with the read error", r)
+	}
+	// 连续失败时仍保留最后一次成功的值
+	r = p.Poll(context.Background(), targets)["plc"][0]
+	if r.Quality != modbus.QUALITY_STALE || !bytes.Equal(r.Value.([]byte), []byte{0x12, 0x34}) {
+		t.Fatalf("second poll after failure = %+v, want stale 12 34", r)
+	}
+}
+
+func TestMultiPollerBadWithoutPreviousValue(t *testing.T) {
+	errDown := errors.New("device down")
+	ok := &modbustest.MockSlaver{ReadCoilsFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		return []byte{0x01, 0x05}, nil
+	}}
+	down := &modbustest.MockSlaver{ReadCoilsFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		return nil, errDown
+	}}
+	req := modbus.PollRequest{Code: modbus.READ_COILS, Address: 0, Quantity: 3}
+	results := modbus.NewMultiPoller(2).Poll(context.Background(), map[string]modbus.PollTarget{
+		"ok":   {Slaver: ok, Requests: []modbus.PollRequest{req}},
+		"down": {Slaver: down, Requests: []modbus.PollRequest{req}},
+	})
+
+	if r := results["ok"][0]; r.Quality != modbus.QUALITY_GOOD || !bytes.Equal(r.Value.([]byte), []byte{0x05}) {
+		t.Fatalf("ok = %+v, want good 05", r)
+	}
+	// 一个设备失败不影响其他设备,且没有旧值时为QUALITY_BAD
+	if r := results["down"][0]; !errors.Is(r.Err, errDown) || r.Quality != modbus.QUALITY_BAD || r.Value != nil {
+		t.Fatalf("down = %+v, want bad without value", r)
+	}
+}
+
+func TestMultiPollerContextCancelled(t *testing.T) {
+	calls := 0
+	s := &modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		calls++
+		return []byte{0x02, 0x00, 0x00}, nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := modbus.PollRequest{Code: modbus.READ_HOLDING_REGISTERS, Address: 0, Quantity: 1}
+	rs := modbus.NewMultiPoller(1).Poll(ctx, map[string]modbus.PollTarget{"plc": {Slaver: s, Requests: []modbus.PollRequest{req, req}}})["plc"]
+	if len(rs) != 2 || calls != 0 {
+		t.Fatalf("%v results after %v calls, want 2 results and no request", len(rs), calls)
+	}
+	for _, r := range rs {
+		if !errors.Is(r.Err, context.Canceled) || r.Quality != modbus.QUALITY_BAD {
+			t.Fatalf("got %+v, want context.Canceled with bad quality", r)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package modbus
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type rateLimitTransporter struct {
+	Transporter
+	interval time.Duration
+	mu       sync.Mutex
+	last     time.Time
+}
+
+// RateLimit 包装传输层,保证相邻两次发送之间至少间隔interval,用于保护无法承受高频轮询的设备
+// 限速只作用于返回的传输层本身,而非全局;等待期间上下文结束时返回ctx.Err()
+func RateLimit(transporter Transporter, interval time.Duration) Transporter {
+	return &rateLimitTransporter{Transporter: transporter, interval: interval}
+}
+
+func (t *rateLimitTransporter) Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) (readu []byte, err error) {
+	if err := t.wait(ctx); err != nil {
+		return nil, err
+	}
+	return t.Transporter.Send(ctx, adu, waitTimes, timeout)
+}
+
+// wait 等待至距上次发送至少interval,并记录本次发送时刻
+// 发送时刻只在真正发送时记录,等待期间被取消的调用不会推迟后续的发送
+func (t *rateLimitTransporter) wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t.mu.Lock()
+		now := clk.Now()
+		at := t.last.Add(t.interval)
+		if !now.Before(at) {
+			t.last = now
+			t.mu.Unlock()
+			return nil
+		}
+		t.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clk.After(at.Sub(now)):
+		}
+	}
+}
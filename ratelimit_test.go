@@ -0,0 +1,81 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestRateLimitCancelledWaitDoesNotDelay(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	start := time.Unix(0, 0)
+	clock := modbus.NewFakeClock(start)
+	defer modbus.SetClock(clock)()
+	var sent []time.Time
+	tr := modbus.RateLimit(&modbustest.MockTransporter{SendFunc: func(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error) {
+		sent = append(sent, clock.Now())
+		return adu, nil
+	}}, interval)
+	send := func(ctx context.Context) <-chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := tr.Send(ctx, nil, 0, 0)
+			done <- err
+		}()
+		return done
+	}
+
+	if err := <-send(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 第二次发送在等待间隔时被取消
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := send(ctx)
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	// 第三次发送只需等待首次发送后的一个间隔,被取消的调用不占用发送时刻
+	third := send(context.Background())
+	clock.BlockUntil(2)
+	clock.Advance(interval)
+	select {
+	case err := <-third:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("send still waiting one interval after the first send; the cancelled call kept its slot")
+	}
+	if len(sent) != 2 || !sent[1].Equal(start.Add(interval)) {
+		t.Fatalf("sent at %v, want %v and %v", sent, start, start.Add(interval))
+	}
+}
+
+func TestRateLimitSpacing(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	tr := modbus.RateLimit(&modbustest.MockTransporter{SendFunc: func(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) ([]byte, error) {
+		return adu, nil
+	}}, interval)
+
+	if _, err := tr.Send(context.Background(), nil, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(interval)
+	// 距上次发送已满一个间隔,无需等待
+	if _, err := tr.Send(context.Background(), nil, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := clock.Timers(); n != 0 {
+		t.Fatalf("%v timers pending, want none", n)
+	}
+}
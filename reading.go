@@ -0,0 +1,32 @@
+package modbus
+
+import "time"
+
+// 读数质量
+type Quality byte
+
+const (
+	QUALITY_GOOD  Quality = iota // 本次读取成功,值为最新
+	QUALITY_STALE                // 本次读取失败,值为上次成功读取的旧值
+	QUALITY_BAD                  // 读取失败且没有可用的旧值
+)
+
+func (q Quality) String() string {
+	switch q {
+	case QUALITY_GOOD:
+		return "good"
+	case QUALITY_STALE:
+		return "stale"
+	case QUALITY_BAD:
+		return "bad"
+	}
+	return "unknown"
+}
+
+// 带时间戳和质量标识的读数,Err为本次读取的错误
+type Reading struct {
+	Timestamp time.Time
+	Value     any
+	Quality   Quality
+	Err       error
+}
@@ -0,0 +1,42 @@
+package modbus
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Recorder 包装传输层并保留最近一次收发的原始ADU,便于排查单次失败的请求
+// 只保留最近一次交互,每次Send都会覆盖
+type Recorder struct {
+	Transporter
+
+	mu       sync.Mutex
+	request  []byte
+	response []byte
+}
+
+// NewRecorder 创建包装transporter的Recorder
+func NewRecorder(transporter Transporter) *Recorder {
+	return &Recorder{Transporter: transporter}
+}
+
+func (r *Recorder) Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) (readu []byte, err error) {
+	readu, err = r.Transporter.Send(ctx, adu, waitTimes, timeout)
+	r.mu.Lock()
+	r.request = append(r.request[:0], adu...)
+	r.response = append(r.response[:0], readu...)
+	r.mu.Unlock()
+	return readu, err
+}
+
+// LastExchange 返回最近一次请求和响应ADU的副本,请求失败时response可能为空
+func (r *Recorder) LastExchange() (request, response []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	request = make([]byte, len(r.request))
+	copy(request, r.request)
+	response = make([]byte, len(r.response))
+	copy(response, r.response)
+	return request, response
+}
@@ -0,0 +1,192 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// 多寄存器数值的字节序,由两个相互独立的开关位组合而成,可覆盖各厂商的非标准排列
+type ByteOrder uint8
+
+const (
+	WORD_SWAP ByteOrder = 1 << 0 // 寄存器(字)按低字在前排列
+	BYTE_SWAP ByteOrder = 1 << 1 // 每个寄存器内低字节在前
+)
+
+// 常用字节序,以32位值为例,A为最高有效字节
+const (
+	ABCD ByteOrder = 0                     // 大端,高字在前(Modbus标准)
+	CDAB ByteOrder = WORD_SWAP             // 字交换,低字在前
+	BADC ByteOrder = BYTE_SWAP             // 字内字节交换,高字在前
+	DCBA ByteOrder = WORD_SWAP | BYTE_SWAP // 小端,低字在前且字内字节交换
+)
+
+func (order ByteOrder) String() string {
+	switch order {
+	case ABCD:
+		return "ABCD"
+	case CDAB:
+		return "CDAB"
+	case BADC:
+		return "BADC"
+	case DCBA:
+		return "DCBA"
+	}
+	return fmt.Sprintf("ByteOrder(%d)", uint8(order))
+}
+
+// toBigEndian 将按order排列的寄存器数据转换为大端字节,len(data)须为偶数
+func (order ByteOrder) toBigEndian(data []byte) []byte {
+	b := make([]byte, len(data))
+	n := len(data) / 2
+	for i := 0; i < n; i++ {
+		src := i
+		if order&WORD_SWAP != 0 {
+			src = n - 1 - i
+		}
+		hi, lo := data[src*2], data[src*2+1]
+		if order&BYTE_SWAP != 0 {
+			hi, lo = lo, hi
+		}
+		b[i*2], b[i*2+1] = hi, lo
+	}
+	return b
+}
+
+// 响应格式错误
+var ErrInvalidResponse = errors.New("modbus: invalid response")
+
+// 读响应的字节数字段与请求数量或实际数据长度不一致
+// 可通过errors.Is(err, ErrInvalidResponse)判断
+type ByteCountError struct {
+	Declared int // 响应中字节数字段的值
+	Actual   int // 响应中字节数字段之后实际的数据长度
+	Expected int // 按请求数量计算的数据长度
+}
+
+func (e *ByteCountError) Error() string {
+	return fmt.Sprintf("%v: byte count '%v', payload '%v' bytes, expected '%v' bytes", ErrInvalidResponse, e.Declared, e.Actual, e.Expected)
+}
+
+func (e *ByteCountError) Unwrap() error {
+	return ErrInvalidResponse
+}
+
+// RegistersToUint32 将2个寄存器(4字节)按指定字节序组合为uint32
+func RegistersToUint32(data []byte, order ByteOrder) (uint32, error) {
+	if len(data) < 4 {
+		return 0, fmt.Errorf("%w: '%v' bytes, need '4' bytes for a 32-bit value", ErrInvalidResponse, len(data))
+	}
+	return binary.BigEndian.Uint32(order.toBigEndian(data[:4])), nil
+}
+
+// RegistersToUint64 将4个寄存器(8字节)按指定字节序组合为uint64
+func RegistersToUint64(data []byte, order ByteOrder) (uint64, error) {
+	if len(data) < 8 {
+		return 0, fmt.Errorf("%w: '%v' bytes, need '8' bytes for a 64-bit value", ErrInvalidResponse, len(data))
+	}
+	return binary.BigEndian.Uint64(order.toBigEndian(data[:8])), nil
+}
+
+// Uint32ToRegisters 将uint32按指定字节序拆分为2个寄存器(4字节),与RegistersToUint32互逆
+func Uint32ToRegisters(v uint32, order ByteOrder) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	// 字交换与字内字节交换均为自逆操作,同一变换即可由大端还原为指定字节序
+	return order.toBigEndian(b[:])
+}
+
+// ReadUint32Point 读取远程设备中2个连续保持寄存器,并按指定字节序组合为一个32位无符号点
+// address: 2字节,寄存器起始地址,寻址范围[0x0000-0xFFFE]
+func ReadUint32Point(ctx context.Context, s Slaver, address uint16, order ByteOrder) (uint32, error) {
+	if address > 0xFFFE {
+		return 0, fmt.Errorf("%w: address '%v' leaves no room for a second register", ErrOutOfRange, address)
+	}
+	results, err := s.ReadHoldingRegisters(ctx, address, 2)
+	if err != nil {
+		return 0, err
+	}
+	data, err := registerData(results, 2)
+	if err != nil {
+		return 0, err
+	}
+	return RegistersToUint32(data, order)
+}
+
+// ReadInt32Point 读取远程设备中2个连续保持寄存器,并按指定字节序组合为一个32位有符号点
+// address: 2字节,寄存器起始地址,寻址范围[0x0000-0xFFFE]
+func ReadInt32Point(ctx context.Context, s Slaver, address uint16, order ByteOrder) (int32, error) {
+	v, err := ReadUint32Point(ctx, s, address, order)
+	return int32(v), err
+}
+
+// ReadHoldingRegistersInto 读取远程设备中保持寄存器,并将寄存器值写入调用方提供的dst,返回写入的字节数
+// dst的长度至少为quantity*2字节,否则在发送请求前返回错误
+func ReadHoldingRegistersInto(ctx context.Context, s Slaver, address, quantity uint16, dst []byte) (int, error) {
+	if err := ValidateQuantity(READ_HOLDING_REGISTERS, quantity); err != nil {
+		return 0, err
+	}
+	if n := int(quantity) * 2; len(dst) < n {
+		return 0, fmt.Errorf("%w: destination of '%v' bytes, quantity '%v' needs '%v' bytes", ErrOutOfRange, len(dst), quantity, n)
+	}
+	results, err := s.ReadHoldingRegisters(ctx, address, quantity)
+	if err != nil {
+		return 0, err
+	}
+	data, err := registerData(results, quantity)
+	if err != nil {
+		return 0, err
+	}
+	return copy(dst, data), nil
+}
+
+// ReadHoldingRegistersUint16Into 读取远程设备中保持寄存器,并将寄存器值直接解码到调用方提供的dst
+// len(dst)至少为quantity,否则在发送请求前返回错误
+func ReadHoldingRegistersUint16Into(ctx context.Context, s Slaver, address, quantity uint16, dst []uint16) error {
+	if err := ValidateQuantity(READ_HOLDING_REGISTERS, quantity); err != nil {
+		return err
+	}
+	if len(dst) < int(quantity) {
+		return fmt.Errorf("%w: destination of '%v' registers, quantity '%v'", ErrOutOfRange, len(dst), quantity)
+	}
+	results, err := s.ReadHoldingRegisters(ctx, address, quantity)
+	if err != nil {
+		return err
+	}
+	data, err := registerData(results, quantity)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < int(quantity); i++ {
+		dst[i] = binary.BigEndian.Uint16(data[i*2:])
+	}
+	return nil
+}
+
+// ParseReadWriteMultipleRegisters 解析读写多个寄存器(0x17)的响应数据(字节数(1) + 读寄存器值(N*2)),
+// 校验字节数等于readQuantity*2后返回解码的读寄存器值
+func ParseReadWriteMultipleRegisters(results []byte, readQuantity uint16) ([]uint16, error) {
+	data, err := registerData(results, readQuantity)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint16, readQuantity)
+	for i := range values {
+		values[i] = binary.BigEndian.Uint16(data[i*2:])
+	}
+	return values, nil
+}
+
+// registerData 校验读寄存器响应(字节数(1) + 寄存器值(N*2))并返回寄存器值部分
+func registerData(results []byte, quantity uint16) ([]byte, error) {
+	if len(results) < 1 {
+		return nil, fmt.Errorf("%w: empty response", ErrInvalidResponse)
+	}
+	count := int(results[0])
+	if count != int(quantity)*2 || len(results)-1 != count {
+		return nil, &ByteCountError{Declared: count, Actual: len(results) - 1, Expected: int(quantity) * 2}
+	}
+	return results[1:], nil
+}
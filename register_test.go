@@ -0,0 +1,131 @@
+package modbus_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestByteCountMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []byte
+		want    modbus.ByteCountError
+	}{
+		// 请求2个寄存器,字节数字段声明为2,实际4字节
+		{"wrong byte count", []byte{0x02, 0x00, 0x01, 0x00, 0x02}, modbus.ByteCountError{Declared: 2, Actual: 4, Expected: 4}},
+		// 字节数字段正确,但数据被截断
+		{"truncated payload", []byte{0x04, 0x00, 0x01, 0x00}, modbus.ByteCountError{Declared: 4, Actual: 3, Expected: 4}},
+		// 字节数字段与数据一致,但与请求数量不符
+		{"wrong quantity", []byte{0x02, 0x00, 0x01}, modbus.ByteCountError{Declared: 2, Actual: 2, Expected: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := modbustest.NewScriptedSlaver(map[byte]modbustest.Response{
+				modbus.READ_HOLDING_REGISTERS: {Results: tt.results},
+			})
+			_, err := modbus.ReadHoldingRegistersInto(context.Background(), s, 0, 2, make([]byte, 4))
+			if !errors.Is(err, modbus.ErrInvalidResponse) {
+				t.Fatalf("got %v, want ErrInvalidResponse", err)
+			}
+			var e *modbus.ByteCountError
+			if !errors.As(err, &e) {
+				t.Fatalf("got %T, want *ByteCountError", err)
+			}
+			if *e != tt.want {
+				t.Fatalf("got %+v, want %+v", *e, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteOrderTruthTable(t *testing.T) {
+	// 同一个值在WORD_SWAP/BYTE_SWAP四种组合下的寄存器排列
+	tests := []struct {
+		order  modbus.ByteOrder
+		data32 []byte // 0x11223344
+		data64 []byte // 0x1122334455667788
+	}{
+		{modbus.ABCD, []byte{0x11, 0x22, 0x33, 0x44}, []byte{0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88}},
+		{modbus.CDAB, []byte{0x33, 0x44, 0x11, 0x22}, []byte{0x77, 0x88, 0x55, 0x66, 0x33, 0x44, 0x11, 0x22}},
+		{modbus.BADC, []byte{0x22, 0x11, 0x44, 0x33}, []byte{0x22, 0x11, 0x44, 0x33, 0x66, 0x55, 0x88, 0x77}},
+		{modbus.DCBA, []byte{0x44, 0x33, 0x22, 0x11}, []byte{0x88, 0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11}},
+	}
+	for _, tt := range tests {
+		got32, err := modbus.RegistersToUint32(tt.data32, tt.order)
+		if err != nil || got32 != 0x11223344 {
+			t.Errorf("RegistersToUint32(% X, %v) = (0x%08X, %v), want 0x11223344", tt.data32, tt.order, got32, err)
+		}
+		if back := modbus.Uint32ToRegisters(0x11223344, tt.order); !bytes.Equal(back, tt.data32) {
+			t.Errorf("Uint32ToRegisters(0x11223344, %v) = % X, want % X", tt.order, back, tt.data32)
+		}
+		got64, err := modbus.RegistersToUint64(tt.data64, tt.order)
+		if err != nil || got64 != 0x1122334455667788 {
+			t.Errorf("RegistersToUint64(% X, %v) = (0x%016X, %v), want 0x1122334455667788", tt.data64, tt.order, got64, err)
+		}
+	}
+}
+
+// 返回quantity个寄存器的固定响应,响应本身不产生分配
+func registerSlaver(quantity uint16) *modbustest.MockSlaver {
+	results := make([]byte, 1+int(quantity)*2)
+	results[0] = byte(quantity * 2)
+	for i := 1; i < len(results); i += 2 {
+		results[i+1] = byte(i / 2)
+	}
+	return &modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		return results, nil
+	}}
+}
+
+func TestReadHoldingRegistersUint16Into(t *testing.T) {
+	s := registerSlaver(3)
+	dst := make([]uint16, 4)
+	if err := modbus.ReadHoldingRegistersUint16Into(context.Background(), s, 0, 3, dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []uint16{0, 1, 2, 0}; fmt.Sprint(dst) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", dst, want)
+	}
+	if err := modbus.ReadHoldingRegistersUint16Into(context.Background(), s, 0, 3, make([]uint16, 2)); !errors.Is(err, modbus.ErrOutOfRange) {
+		t.Fatalf("short destination: got %v, want ErrOutOfRange", err)
+	}
+}
+
+func BenchmarkReadHoldingRegistersUint16Into(b *testing.B) {
+	s := registerSlaver(125)
+	dst := make([]uint16, 125)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := modbus.ReadHoldingRegistersUint16Into(ctx, s, 0, 125, dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 对照: 读取后为每次结果分配新的[]uint16
+func BenchmarkReadHoldingRegistersUint16Alloc(b *testing.B) {
+	s := registerSlaver(125)
+	ctx := context.Background()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		results, err := s.ReadHoldingRegisters(ctx, 0, 125)
+		if err != nil {
+			b.Fatal(err)
+		}
+		values := make([]uint16, 125)
+		for j := range values {
+			values[j] = binary.BigEndian.Uint16(results[1+j*2:])
+		}
+		benchmarkSink = values
+	}
+}
+
+var benchmarkSink []uint16
@@ -0,0 +1,194 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 寄存器点
+type Point struct {
+	Name string
+	// 读功能码,READ_HOLDING_REGISTERS或READ_INPUT_REGISTERS
+	Code    byte
+	Address uint16
+	Type    DataType
+	// 该点自身的字节序,同一设备中不同点可以不同
+	Order ByteOrder
+}
+
+// RegisterMap 一组按名称区分的寄存器点,可同时包含保持寄存器和输入寄存器
+type RegisterMap struct {
+	points []Point
+	names  map[string]struct{}
+}
+
+// NewRegisterMap 创建RegisterMap
+func NewRegisterMap() *RegisterMap {
+	return &RegisterMap{names: make(map[string]struct{})}
+}
+
+// Add 添加寄存器点,名称重复、功能码或类型非法、地址越界时返回错误
+func (m *RegisterMap) Add(p Point) error {
+	if _, ok := m.names[p.Name]; ok {
+		return fmt.Errorf("modbus: duplicate point name '%v'", p.Name)
+	}
+	if p.Code != READ_HOLDING_REGISTERS && p.Code != READ_INPUT_REGISTERS {
+		return fmt.Errorf("%w: point '%v' function '%v' is not a register read", ErrOutOfRange, p.Name, p.Code)
+	}
+	n := p.Type.Registers()
+	if n == 0 {
+		return fmt.Errorf("modbus: point '%v' has unknown data type '%v'", p.Name, p.Type)
+	}
+	if int(p.Address)+int(n) > 0x10000 {
+		return fmt.Errorf("%w: point '%v' at address '%v' exceeds the address space", ErrOutOfRange, p.Name, p.Address)
+	}
+	m.names[p.Name] = struct{}{}
+	m.points = append(m.points, p)
+	return nil
+}
+
+// 合并后的一次读请求及其覆盖的点
+type readRange struct {
+	PollRequest
+	points []Point
+}
+
+// BatchReader 将RegisterMap中的点合并为最少的读请求(按功能码分别合并)后读取并解码
+type BatchReader struct {
+	s Slaver
+	// 相邻两个点之间允许一并读取的最大空隙寄存器数,默认为0即只合并连续或重叠的点
+	MaxGap uint16
+	// 为true时,合并后的读请求失败会拆回各点单独重读,只有真正读取失败的点报告错误
+	SplitOnFailure bool
+}
+
+// 单个点的读取错误
+type PointError struct {
+	Name string
+	Err  error
+}
+
+func (e *PointError) Error() string {
+	return fmt.Sprintf("modbus: point '%v': %v", e.Name, e.Err)
+}
+
+func (e *PointError) Unwrap() error {
+	return e.Err
+}
+
+// 批量读取中部分点失败
+type BatchReadError struct {
+	Failed []*PointError
+}
+
+func (e *BatchReadError) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		failed[i] = f.Name
+	}
+	return fmt.Sprintf("modbus: %v points failed: %s", len(e.Failed), strings.Join(failed, ", "))
+}
+
+func (e *BatchReadError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// NewBatchReader 创建BatchReader
+func NewBatchReader(s Slaver) *BatchReader {
+	return &BatchReader{s: s}
+}
+
+// Read 读取RegisterMap中的全部点,返回以点名称为键的解码值,与点来自保持寄存器还是输入寄存器无关
+// 默认任一读请求失败时返回该错误;SplitOnFailure为true时返回成功读取的值以及列出失败点的*BatchReadError
+func (r *BatchReader) Read(ctx context.Context, m *RegisterMap) (map[string]any, error) {
+	values := make(map[string]any, len(m.points))
+	batch := &BatchReadError{}
+	for _, rr := range r.ranges(m.points) {
+		data, err := Poll(ctx, r.s, rr.PollRequest)
+		if err != nil {
+			if !r.SplitOnFailure {
+				return nil, fmt.Errorf("modbus: reading function '%v' %v: %w", rr.Code, AddressRange{rr.Address, rr.Quantity}, err)
+			}
+			if len(rr.points) == 1 {
+				batch.Failed = append(batch.Failed, &PointError{Name: rr.points[0].Name, Err: err})
+				continue
+			}
+			for _, p := range rr.points {
+				v, err := r.readPoint(ctx, p)
+				if err != nil {
+					batch.Failed = append(batch.Failed, &PointError{Name: p.Name, Err: err})
+					continue
+				}
+				values[p.Name] = v
+			}
+			continue
+		}
+		for _, p := range rr.points {
+			offset := int(p.Address-rr.Address) * 2
+			v, err := p.Type.Decode(data[offset:], p.Order)
+			if err != nil {
+				if !r.SplitOnFailure {
+					return nil, fmt.Errorf("modbus: decoding point '%v': %w", p.Name, err)
+				}
+				batch.Failed = append(batch.Failed, &PointError{Name: p.Name, Err: err})
+				continue
+			}
+			values[p.Name] = v
+		}
+	}
+	if len(batch.Failed) > 0 {
+		return values, batch
+	}
+	return values, nil
+}
+
+// readPoint 单独读取并解码一个点
+func (r *BatchReader) readPoint(ctx context.Context, p Point) (any, error) {
+	data, err := Poll(ctx, r.s, PollRequest{Code: p.Code, Address: p.Address, Quantity: p.Type.Registers()})
+	if err != nil {
+		return nil, err
+	}
+	return p.Type.Decode(data, p.Order)
+}
+
+// ranges 按功能码分组,按地址排序后将间隔不超过MaxGap且总跨度不超过单帧上限的点合并为一次读取
+func (r *BatchReader) ranges(points []Point) []readRange {
+	sorted := make([]Point, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Code != sorted[j].Code {
+			return sorted[i].Code < sorted[j].Code
+		}
+		return sorted[i].Address < sorted[j].Address
+	})
+	var ranges []readRange
+	for _, p := range sorted {
+		end := int(p.Address) + int(p.Type.Registers())
+		if n := len(ranges); n > 0 {
+			last := &ranges[n-1]
+			lastEnd := int(last.Address) + int(last.Quantity)
+			if last.Code == p.Code && int(p.Address) <= lastEnd+int(r.MaxGap) {
+				if end <= lastEnd {
+					last.points = append(last.points, p)
+					continue
+				}
+				if end-int(last.Address) <= int(MAX_READ_REGISTERS) {
+					last.Quantity = uint16(end - int(last.Address))
+					last.points = append(last.points, p)
+					continue
+				}
+			}
+		}
+		ranges = append(ranges, readRange{
+			PollRequest: PollRequest{Code: p.Code, Address: p.Address, Quantity: p.Type.Registers()},
+			points:      []Point{p},
+		})
+	}
+	return ranges
+}
@@ -0,0 +1,100 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestBatchReaderPerPointByteOrder(t *testing.T) {
+	m := modbus.NewRegisterMap()
+	points := []modbus.Point{
+		{Name: "big", Code: modbus.READ_HOLDING_REGISTERS, Address: 0, Type: modbus.TYPE_FLOAT32, Order: modbus.ABCD},
+		{Name: "swapped", Code: modbus.READ_HOLDING_REGISTERS, Address: 2, Type: modbus.TYPE_FLOAT32, Order: modbus.CDAB},
+	}
+	for _, p := range points {
+		if err := m.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	requests := 0
+	s := &modbustest.MockSlaver{ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		requests++
+		if address != 0 || quantity != 4 {
+			t.Fatalf("read %v registers from %v, want 4 from 0", quantity, address)
+		}
+		// 1.5 = 0x3FC00000,前一个点高字在前,后一个点低字在前
+		return []byte{0x08, 0x3F, 0xC0, 0x00, 0x00, 0x00, 0x00, 0x3F, 0xC0}, nil
+	}}
+
+	values, err := modbus.NewBatchReader(s).Read(context.Background(), m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("%v requests, want the two points coalesced into 1", requests)
+	}
+	for _, name := range []string{"big", "swapped"} {
+		if v, ok := values[name].(float32); !ok || v != 1.5 {
+			t.Errorf("%s = %v (%T), want 1.5", name, values[name], values[name])
+		}
+	}
+}
+
+func TestBatchReaderSplitOnFailure(t *testing.T) {
+	m := modbus.NewRegisterMap()
+	points := []modbus.Point{
+		{Name: "a", Code: modbus.READ_HOLDING_REGISTERS, Address: 0, Type: modbus.TYPE_UINT16},
+		{Name: "b", Code: modbus.READ_HOLDING_REGISTERS, Address: 1, Type: modbus.TYPE_UINT16},
+		{Name: "c", Code: modbus.READ_HOLDING_REGISTERS, Address: 2, Type: modbus.TYPE_UINT16},
+		{Name: "d", Code: modbus.READ_INPUT_REGISTERS, Address: 10, Type: modbus.TYPE_UINT16},
+	}
+	for _, p := range points {
+		if err := m.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 寄存器1不存在:覆盖它的合并读取和对它的单独读取都返回异常
+	s := &modbustest.MockSlaver{
+		ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			if address <= 1 && int(address)+int(quantity) > 1 {
+				return nil, &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.ILLEGAL_DATA_ADDRESS}
+			}
+			return []byte{0x02, 0x00, byte(address)}, nil
+		},
+		ReadInputRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			return []byte{0x02, 0x00, byte(address)}, nil
+		},
+	}
+
+	r := modbus.NewBatchReader(s)
+	if values, err := r.Read(context.Background(), m); err == nil || values != nil {
+		t.Fatalf("without SplitOnFailure got %v, %v, want the grouped read error", values, err)
+	}
+
+	r.SplitOnFailure = true
+	values, err := r.Read(context.Background(), m)
+	var batch *modbus.BatchReadError
+	if !errors.As(err, &batch) {
+		t.Fatalf("got %v, want *BatchReadError", err)
+	}
+	if len(batch.Failed) != 1 || batch.Failed[0].Name != "b" {
+		t.Fatalf("failed points = %v, want only b", err)
+	}
+	var e *modbus.Error
+	if !errors.As(err, &e) || e.ExceptionCode != modbus.ILLEGAL_DATA_ADDRESS {
+		t.Fatalf("%v does not unwrap to the device exception", err)
+	}
+	want := map[string]uint16{"a": 0, "c": 2, "d": 10}
+	if len(values) != len(want) {
+		t.Fatalf("values = %v, want %v", values, want)
+	}
+	for name, v := range want {
+		if values[name] != v {
+			t.Errorf("%s = %v (%T), want %v", name, values[name], values[name], v)
+		}
+	}
+}
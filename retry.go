@@ -0,0 +1,18 @@
+package modbus
+
+import "fmt"
+
+// 重试次数用尽,Last为最后一次尝试的错误
+// Unwrap返回Last,因此errors.Is/errors.As仍可匹配底层原因
+type RetryExhaustedError struct {
+	Attempts int
+	Last     error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("modbus: gave up after %v attempts: %v", e.Attempts, e.Last)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Last
+}
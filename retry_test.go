@@ -0,0 +1,35 @@
+package modbus_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/kokutas/modbus"
+)
+
+func TestRetryExhaustedErrorUnwrap(t *testing.T) {
+	exception := &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.SERVER_DEVICE_BUSY}
+	last := fmt.Errorf("read point: %w", exception)
+	err := fmt.Errorf("poll: %w", &modbus.RetryExhaustedError{Attempts: 5, Last: last})
+
+	var exhausted *modbus.RetryExhaustedError
+	if !errors.As(err, &exhausted) || exhausted.Attempts != 5 {
+		t.Fatalf("got %v, want *RetryExhaustedError with 5 attempts", err)
+	}
+	var e *modbus.Error
+	if !errors.As(err, &e) || e != exception {
+		t.Fatalf("errors.As did not reach the underlying *Error through %v", err)
+	}
+	if !errors.Is(err, last) {
+		t.Fatalf("errors.Is did not match the last error")
+	}
+
+	timeout := &modbus.RetryExhaustedError{Attempts: 3, Last: modbus.ErrNoResponse}
+	if !errors.Is(timeout, modbus.ErrNoResponse) {
+		t.Fatalf("errors.Is(%v, ErrNoResponse) = false", timeout)
+	}
+	if want := "modbus: gave up after 3 attempts: modbus: no response"; timeout.Error() != want {
+		t.Fatalf("Error() = %q, want %q", timeout.Error(), want)
+	}
+}
@@ -0,0 +1,39 @@
+package modbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WriteScaled 将工程值换算为寄存器原始值后写入单个保持寄存器
+// 原始值 = round((value-offset)/scale)
+// signed: 为true时原始值限幅到int16范围[-32768,32767],否则限幅到uint16范围[0,65535],不会静默回绕
+// 原始值为NaN或无穷大时返回ErrOutOfRange
+func WriteScaled(ctx context.Context, s Slaver, address uint16, value float64, scale, offset float64, signed bool) error {
+	if scale == 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return errors.New("modbus: scale must be a finite non-zero number")
+	}
+	raw := math.Round((value - offset) / scale)
+	if math.IsNaN(raw) || math.IsInf(raw, 0) {
+		return fmt.Errorf("%w: scaled value of '%v' is not a finite number", ErrOutOfRange, value)
+	}
+	lo, hi := float64(0), float64(math.MaxUint16)
+	if signed {
+		lo, hi = math.MinInt16, math.MaxInt16
+	}
+	if raw < lo {
+		raw = lo
+	} else if raw > hi {
+		raw = hi
+	}
+	var v uint16
+	if signed {
+		v = uint16(int16(raw))
+	} else {
+		v = uint16(raw)
+	}
+	_, err := s.WriteSingleRegister(ctx, address, v)
+	return err
+}
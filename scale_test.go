@@ -0,0 +1,54 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestWriteScaled(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  float64
+		scale  float64
+		offset float64
+		signed bool
+		want   uint16
+	}{
+		{"unsigned", 12.34, 0.01, 0, false, 1234},
+		{"offset", 20, 0.5, 10, false, 20},
+		{"unsigned clamped high", 1e6, 1, 0, false, 0xFFFF},
+		{"unsigned clamped low", -5, 1, 0, false, 0},
+		{"signed negative", -12.5, 0.1, 0, true, 0xFF83},
+		{"signed clamped high", 40000, 1, 0, true, 0x7FFF},
+		{"signed clamped low", -40000, 1, 0, true, 0x8000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uint16
+			s := &modbustest.MockSlaver{WriteSingleRegisterFunc: func(ctx context.Context, address, value uint16) ([]byte, error) {
+				got = value
+				return nil, nil
+			}}
+			if err := modbus.WriteScaled(context.Background(), s, 0, tt.value, tt.scale, tt.offset, tt.signed); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("wrote 0x%04X, want 0x%04X", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteScaledNotFinite(t *testing.T) {
+	s := &modbustest.MockSlaver{}
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if err := modbus.WriteScaled(context.Background(), s, 0, v, 1, 0, false); !errors.Is(err, modbus.ErrOutOfRange) {
+			t.Fatalf("WriteScaled(%v) = %v, want ErrOutOfRange", v, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package modbus
+
+import "fmt"
+
+// 功能码常量 report server id (serial line only)
+const (
+	REPORT_SERVER_ID byte = 17 // 17(0x11)
+)
+
+// 运行指示状态
+const (
+	RUN_INDICATOR_OFF byte = 0x00
+	RUN_INDICATOR_ON  byte = 0xFF
+)
+
+// 报告从站ID(0x11)的响应
+type ServerID struct {
+	ID []byte
+	// 运行指示状态为ON(0xFF)时为true
+	Running bool
+	// 运行指示状态之后的设备附加数据
+	Additional []byte
+}
+
+// ParseServerID 解析报告从站ID(0x11)的响应数据: 字节数(1) + 从站ID(idLength) + 运行指示状态(1) + 附加数据
+// 从站ID的长度由设备定义,需由调用方按设备文档给出
+func ParseServerID(results []byte, idLength int) (*ServerID, error) {
+	if len(results) < 1 || int(results[0]) != len(results)-1 {
+		return nil, fmt.Errorf("%w: report server id response of '%v' bytes has inconsistent byte count", ErrInvalidResponse, len(results))
+	}
+	data := results[1:]
+	if idLength < 0 || len(data) < idLength+1 {
+		return nil, fmt.Errorf("%w: '%v' bytes of data, need '%v' bytes for a '%v' byte server id and the run indicator", ErrInvalidResponse, len(data), idLength+1, idLength)
+	}
+	run := data[idLength]
+	if run != RUN_INDICATOR_OFF && run != RUN_INDICATOR_ON {
+		return nil, fmt.Errorf("%w: run indicator status '0x%02X'", ErrInvalidResponse, run)
+	}
+	return &ServerID{
+		ID:         append([]byte(nil), data[:idLength]...),
+		Running:    run == RUN_INDICATOR_ON,
+		Additional: append([]byte(nil), data[idLength+1:]...),
+	}, nil
+}
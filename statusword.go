@@ -0,0 +1,45 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// StatusWord 为一个16位状态寄存器的各位定义名称,将寄存器值解码为命名的标志
+type StatusWord struct {
+	names [16]string
+}
+
+// Define 为第bit位(0为最低位)定义名称,bit须在[0-15]内
+func (sw *StatusWord) Define(bit uint, name string) error {
+	if bit > 15 {
+		return fmt.Errorf("%w: status word bit '%v' must be between '0' and '15'", ErrOutOfRange, bit)
+	}
+	sw.names[bit] = name
+	return nil
+}
+
+// Decode 将寄存器值解码为已定义名称的位的状态,未定义名称的位不出现在结果中
+func (sw *StatusWord) Decode(value uint16) map[string]bool {
+	flags := make(map[string]bool)
+	for bit, name := range sw.names {
+		if name != "" {
+			flags[name] = value&(1<<uint(bit)) != 0
+		}
+	}
+	return flags
+}
+
+// Read 读取远程设备中address处的保持寄存器并按已定义的位名称解码
+func (sw *StatusWord) Read(ctx context.Context, s Slaver, address uint16) (map[string]bool, error) {
+	results, err := s.ReadHoldingRegisters(ctx, address, 1)
+	if err != nil {
+		return nil, err
+	}
+	data, err := registerData(results, 1)
+	if err != nil {
+		return nil, err
+	}
+	return sw.Decode(binary.BigEndian.Uint16(data)), nil
+}
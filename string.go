@@ -0,0 +1,54 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// ReadString 读取远程设备中registerCount个连续保持寄存器,并解码为字符串(去除末尾的NUL)
+// order: 仅BYTE_SWAP生效,置位时每个寄存器内低字节在前
+func ReadString(ctx context.Context, s Slaver, address uint16, registerCount uint16, order ByteOrder) (string, error) {
+	if err := ValidateQuantity(READ_HOLDING_REGISTERS, registerCount); err != nil {
+		return "", err
+	}
+	results, err := s.ReadHoldingRegisters(ctx, address, registerCount)
+	if err != nil {
+		return "", err
+	}
+	data, err := registerData(results, registerCount)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, len(data))
+	copy(b, data)
+	if order&BYTE_SWAP != 0 {
+		swapRegisterBytes(b)
+	}
+	return string(bytes.TrimRight(b, "\x00")), nil
+}
+
+// WriteString 将字符串写入远程设备中registerCount个连续保持寄存器,不足部分以NUL填充
+// order: 仅BYTE_SWAP生效,置位时每个寄存器内低字节在前
+func WriteString(ctx context.Context, s Slaver, address uint16, registerCount uint16, value string, order ByteOrder) error {
+	if err := ValidateQuantity(WRITE_MULTIPLE_REGISTERS, registerCount); err != nil {
+		return err
+	}
+	if len(value) > int(registerCount)*2 {
+		return fmt.Errorf("%w: string of '%v' bytes does not fit in '%v' registers", ErrOutOfRange, len(value), registerCount)
+	}
+	b := make([]byte, int(registerCount)*2)
+	copy(b, value)
+	if order&BYTE_SWAP != 0 {
+		swapRegisterBytes(b)
+	}
+	_, err := s.WriteMultipleregisters(ctx, address, registerCount, b)
+	return err
+}
+
+// swapRegisterBytes 交换每个寄存器(2字节)内的高低字节
+func swapRegisterBytes(b []byte) {
+	for i := 0; i+1 < len(b); i += 2 {
+		b[i], b[i+1] = b[i+1], b[i]
+	}
+}
@@ -0,0 +1,115 @@
+package modbus
+
+import (
+	"context"
+	"time"
+)
+
+type timeoutSlaver struct {
+	Slaver
+	timeouts map[byte]time.Duration
+	fallback time.Duration
+}
+
+// WithFunctionTimeouts 包装Slaver,按功能码为没有截止时间的上下文设置超时
+// timeouts: 功能码->超时时间,未列出的功能码使用fallback,超时时间不大于0时不设置
+// 优先级: 上下文截止时间 > 单次请求超时(WithTimeout) > 功能码超时(timeouts/fallback) > 传输层默认超时
+// 上下文已有截止时间或通过WithTimeout携带了单次请求超时时,不再设置功能码超时
+// 例如为写多个寄存器(0x10)设置较长的超时,而读寄存器保持较短的超时
+func WithFunctionTimeouts(s Slaver, timeouts map[byte]time.Duration, fallback time.Duration) Slaver {
+	m := make(map[byte]time.Duration, len(timeouts))
+	for code, d := range timeouts {
+		m[code] = d
+	}
+	return &timeoutSlaver{Slaver: s, timeouts: m, fallback: fallback}
+}
+
+// withTimeout 上下文没有截止时间且没有单次请求超时时按功能码code设置超时
+func (s *timeoutSlaver) withTimeout(ctx context.Context, code byte) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	if _, ok := TimeoutFromContext(ctx); ok {
+		return ctx, func() {}
+	}
+	d, ok := s.timeouts[code]
+	if !ok {
+		d = s.fallback
+	}
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+func (s *timeoutSlaver) ReadCoils(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, READ_COILS)
+	defer cancel()
+	return s.Slaver.ReadCoils(ctx, address, quantity)
+}
+
+func (s *timeoutSlaver) ReadDiscreteInputs(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, READ_DISCRETE_INPUTS)
+	defer cancel()
+	return s.Slaver.ReadDiscreteInputs(ctx, address, quantity)
+}
+
+func (s *timeoutSlaver) ReadHoldingRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, READ_HOLDING_REGISTERS)
+	defer cancel()
+	return s.Slaver.ReadHoldingRegisters(ctx, address, quantity)
+}
+
+func (s *timeoutSlaver) ReadInputRegisters(ctx context.Context, address, quantity uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, READ_INPUT_REGISTERS)
+	defer cancel()
+	return s.Slaver.ReadInputRegisters(ctx, address, quantity)
+}
+
+func (s *timeoutSlaver) WriteSingleCoil(ctx context.Context, address, value uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, WRITE_SINGLE_COIL)
+	defer cancel()
+	return s.Slaver.WriteSingleCoil(ctx, address, value)
+}
+
+func (s *timeoutSlaver) WriteSingleRegister(ctx context.Context, address, value uint16) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, WRITE_SINGLE_REGISTER)
+	defer cancel()
+	return s.Slaver.WriteSingleRegister(ctx, address, value)
+}
+
+func (s *timeoutSlaver) ReadExceptionStatus(ctx context.Context) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, READ_EXCEPTION_STATUS)
+	defer cancel()
+	return s.Slaver.ReadExceptionStatus(ctx)
+}
+
+func (s *timeoutSlaver) Diagnostics(ctx context.Context, subFunc uint16, value []byte) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, DIAGNOSTICS)
+	defer cancel()
+	return s.Slaver.Diagnostics(ctx, subFunc, value)
+}
+
+func (s *timeoutSlaver) GetCommEventCounter(ctx context.Context) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, GET_COMM_EVENT_COUNTER)
+	defer cancel()
+	return s.Slaver.GetCommEventCounter(ctx)
+}
+
+func (s *timeoutSlaver) GetCommEventLog(ctx context.Context) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, GET_COMM_EVENT_LOG)
+	defer cancel()
+	return s.Slaver.GetCommEventLog(ctx)
+}
+
+func (s *timeoutSlaver) WriteMultipleCoils(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, WRITE_MULTIPLE_COILS)
+	defer cancel()
+	return s.Slaver.WriteMultipleCoils(ctx, address, quantity, value)
+}
+
+func (s *timeoutSlaver) WriteMultipleregisters(ctx context.Context, address, quantity uint16, value []byte) (results []byte, err error) {
+	ctx, cancel := s.withTimeout(ctx, WRITE_MULTIPLE_REGISTERS)
+	defer cancel()
+	return s.Slaver.WriteMultipleregisters(ctx, address, quantity, value)
+}
@@ -0,0 +1,47 @@
+package modbus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestWithFunctionTimeouts(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	capture := func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		deadline, hasDeadline = ctx.Deadline()
+		return nil, nil
+	}
+	s := modbus.WithFunctionTimeouts(&modbustest.MockSlaver{
+		ReadHoldingRegistersFunc: capture,
+		ReadInputRegistersFunc:   capture,
+		ReadCoilsFunc:            capture,
+	}, map[byte]time.Duration{modbus.READ_HOLDING_REGISTERS: time.Hour}, time.Minute)
+
+	s.ReadHoldingRegisters(context.Background(), 0, 1)
+	if !hasDeadline || time.Until(deadline) < 59*time.Minute {
+		t.Fatalf("per-code timeout not applied: deadline=%v ok=%v", deadline, hasDeadline)
+	}
+
+	s.ReadInputRegisters(context.Background(), 0, 1)
+	if !hasDeadline || time.Until(deadline) > time.Minute {
+		t.Fatalf("fallback timeout not applied: deadline=%v ok=%v", deadline, hasDeadline)
+	}
+
+	s.ReadCoils(modbus.WithTimeout(context.Background(), time.Minute), 0, 1)
+	if hasDeadline {
+		t.Fatalf("per-call WithTimeout must take precedence over the per-code timeout: deadline=%v", deadline)
+	}
+
+	want := time.Now().Add(time.Second)
+	ctx, cancel := context.WithDeadline(context.Background(), want)
+	defer cancel()
+	s.ReadCoils(ctx, 0, 1)
+	if !hasDeadline || !deadline.Equal(want) {
+		t.Fatalf("context deadline must take precedence: got %v, want %v", deadline, want)
+	}
+}
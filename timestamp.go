@@ -0,0 +1,39 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ReadTime 读取2个连续保持寄存器中按order排列的uint32秒数,并换算为自epoch起的时间
+// 适用于使用非Unix纪元(如2000-01-01)记录时间的设备
+func ReadTime(ctx context.Context, s Slaver, address uint16, order ByteOrder, epoch time.Time) (time.Time, error) {
+	v, err := ReadUint32Point(ctx, s, address, order)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return epoch.Add(time.Duration(v) * time.Second), nil
+}
+
+// ReadUnixTime 读取2个连续保持寄存器中按order排列的Unix时间戳(秒)
+func ReadUnixTime(ctx context.Context, s Slaver, address uint16, order ByteOrder) (time.Time, error) {
+	return ReadTime(ctx, s, address, order, time.Unix(0, 0).UTC())
+}
+
+// WriteTime 将t换算为自epoch起的uint32秒数,按order写入2个连续保持寄存器
+// t早于epoch或超出uint32可表示范围时返回ErrOutOfRange
+func WriteTime(ctx context.Context, s Slaver, address uint16, t time.Time, order ByteOrder, epoch time.Time) error {
+	seconds := t.Unix() - epoch.Unix()
+	if seconds < 0 || seconds > math.MaxUint32 {
+		return fmt.Errorf("%w: '%v' is not representable as uint32 seconds since '%v'", ErrOutOfRange, t, epoch)
+	}
+	_, err := WriteMultipleRegisters(ctx, s, address, 2, Uint32ToRegisters(uint32(seconds), order))
+	return err
+}
+
+// WriteUnixTime 将t以Unix时间戳(秒)按order写入2个连续保持寄存器
+func WriteUnixTime(ctx context.Context, s Slaver, address uint16, t time.Time, order ByteOrder) error {
+	return WriteTime(ctx, s, address, t, order, time.Unix(0, 0).UTC())
+}
@@ -0,0 +1,48 @@
+package modbus_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestUnixTimeRoundTrip(t *testing.T) {
+	var stored []byte
+	s := &modbustest.MockSlaver{
+		WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+			stored = append([]byte(nil), value...)
+			return echo(address, quantity), nil
+		},
+		ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			return append([]byte{byte(len(stored))}, stored...), nil
+		},
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := modbus.WriteUnixTime(context.Background(), s, 0, want, modbus.CDAB); err != nil {
+		t.Fatal(err)
+	}
+	// 0x65937D25按低字在前排列
+	if !bytes.Equal(stored, []byte{0x7D, 0x25, 0x65, 0x93}) {
+		t.Fatalf("wrote % X", stored)
+	}
+	got, err := modbus.ReadUnixTime(context.Background(), s, 0, modbus.CDAB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("read back %v, want %v", got, want)
+	}
+}
+
+func TestWriteTimeBeforeEpoch(t *testing.T) {
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := modbus.WriteTime(context.Background(), &modbustest.MockSlaver{}, 0, epoch.Add(-time.Second), modbus.ABCD, epoch)
+	if !errors.Is(err, modbus.ErrOutOfRange) {
+		t.Fatalf("got %v, want ErrOutOfRange", err)
+	}
+}
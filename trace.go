@@ -0,0 +1,58 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// 跟踪输出的方向标识
+const (
+	TRACE_REQUEST  = ">" // 请求
+	TRACE_RESPONSE = "<" // 响应
+)
+
+type traceTransporter struct {
+	Transporter
+	packager Packager
+	mu       sync.Mutex
+	w        io.Writer
+}
+
+// Trace 包装传输层,将每次收发的ADU以带时间戳的十六进制形式写入w
+// 每行格式: 时间戳 方向 功能码 ADU,例如
+// 2006-01-02T15:04:05.000000Z07:00 > fc=0x03 00 01 00 00 00 06 01 03 00 00 00 02
+// 功能码由packager解码得到,解码失败时显示为fc=??
+// 写入w时持有内部锁,可被多个goroutine并发使用
+func Trace(w io.Writer, packager Packager, transporter Transporter) Transporter {
+	return &traceTransporter{Transporter: transporter, packager: packager, w: w}
+}
+
+func (t *traceTransporter) Send(ctx context.Context, adu []byte, waitTimes int, timeout time.Duration) (readu []byte, err error) {
+	t.trace(ctx, TRACE_REQUEST, adu)
+	readu, err = t.Transporter.Send(ctx, adu, waitTimes, timeout)
+	if err != nil {
+		t.write(fmt.Sprintf("%s %s error=%v\n", clk.Now().Format(time.RFC3339Nano), TRACE_RESPONSE, err))
+		return readu, err
+	}
+	t.trace(ctx, TRACE_RESPONSE, readu)
+	return readu, nil
+}
+
+func (t *traceTransporter) trace(ctx context.Context, direction string, adu []byte) {
+	code := "fc=??"
+	if t.packager != nil {
+		if pdu, err := t.packager.Decode(ctx, adu); err == nil && pdu != nil {
+			code = fmt.Sprintf("fc=0x%02X", pdu.Code)
+		}
+	}
+	t.write(fmt.Sprintf("%s %s %s % X\n", clk.Now().Format(time.RFC3339Nano), direction, code, adu))
+}
+
+func (t *traceTransporter) write(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	io.WriteString(t.w, line)
+}
@@ -0,0 +1,80 @@
+package modbus
+
+import (
+	"errors"
+	"fmt"
+)
+
+// 单帧最大数量,读与写的限制不同
+const (
+	MAX_READ_COILS      uint16 = 2000 // 0x07D0 读线圈/离散输入(0x01/0x02)
+	MAX_WRITE_COILS     uint16 = 1968 // 0x07B0 写多个线圈(0x0F)
+	MAX_READ_REGISTERS  uint16 = 125  // 0x007D 读保持/输入寄存器(0x03/0x04)
+	MAX_WRITE_REGISTERS uint16 = 123  // 0x007B 写多个寄存器(0x10)
+)
+
+// 读写多个寄存器(0x17)的数量限制
+const (
+	READ_WRITE_MAX_READ_QUANTITY  uint16 = 125 // 0x007D
+	READ_WRITE_MAX_WRITE_QUANTITY uint16 = 121 // 0x0079
+)
+
+var (
+	// 参数超出允许范围
+	ErrOutOfRange = errors.New("modbus: out of range")
+	// 读写多个寄存器(0x17)的读数量越界,同时匹配ErrOutOfRange
+	ErrReadQuantity = fmt.Errorf("%w: read quantity", ErrOutOfRange)
+	// 读写多个寄存器(0x17)的写数量越界,同时匹配ErrOutOfRange
+	ErrWriteQuantity = fmt.Errorf("%w: write quantity", ErrOutOfRange)
+	// 读写多个寄存器(0x17)的写数据长度与写数量不匹配,同时匹配ErrOutOfRange
+	ErrWriteValueLength = fmt.Errorf("%w: write value length", ErrOutOfRange)
+)
+
+// ValidateReadWriteMultipleRegisters 在发送读写多个寄存器(0x17)请求前校验参数
+// readQuantity: 2字节,读寄存器数量[0x0001-0x007D]
+// writeQuantity: 2字节,写寄存器数量[0x0001-0x0079]
+// value: writeQuantity*2字节,写入数据
+// 读、写两部分分别返回不同的错误,便于调用方定位是哪一半出错(例如读写数量写反)
+func ValidateReadWriteMultipleRegisters(readQuantity, writeQuantity uint16, value []byte) error {
+	if readQuantity < 1 || readQuantity > READ_WRITE_MAX_READ_QUANTITY {
+		return fmt.Errorf("%w: '%v' must be between '1' and '%v'", ErrReadQuantity, readQuantity, READ_WRITE_MAX_READ_QUANTITY)
+	}
+	if writeQuantity < 1 || writeQuantity > READ_WRITE_MAX_WRITE_QUANTITY {
+		return fmt.Errorf("%w: '%v' must be between '1' and '%v'", ErrWriteQuantity, writeQuantity, READ_WRITE_MAX_WRITE_QUANTITY)
+	}
+	if len(value) != int(writeQuantity)*2 {
+		return fmt.Errorf("%w: '%v' bytes does not match write quantity '%v'", ErrWriteValueLength, len(value), writeQuantity)
+	}
+	return nil
+}
+
+// ValidateQuantity 在发送请求前校验读/写多个线圈、寄存器的数量
+// quantity为0或超过该功能码的单帧上限时返回ErrOutOfRange,而不是发送设备无法识别的请求
+// 读线圈/离散输入[1-2000],写多个线圈[1-1968],读寄存器[1-125],写多个寄存器[1-123]
+func ValidateQuantity(code byte, quantity uint16) error {
+	if quantity == 0 {
+		return fmt.Errorf("%w: quantity of function '%v' must not be zero", ErrOutOfRange, code)
+	}
+	var limit uint16
+	var what string
+	switch code {
+	case READ_COILS:
+		limit, what = MAX_READ_COILS, "read coils"
+	case READ_DISCRETE_INPUTS:
+		limit, what = MAX_READ_COILS, "read discrete inputs"
+	case WRITE_MULTIPLE_COILS:
+		limit, what = MAX_WRITE_COILS, "write multiple coils"
+	case READ_HOLDING_REGISTERS:
+		limit, what = MAX_READ_REGISTERS, "read holding registers"
+	case READ_INPUT_REGISTERS:
+		limit, what = MAX_READ_REGISTERS, "read input registers"
+	case WRITE_MULTIPLE_REGISTERS:
+		limit, what = MAX_WRITE_REGISTERS, "write multiple registers"
+	default:
+		return nil
+	}
+	if quantity > limit {
+		return fmt.Errorf("%w: %s quantity '%v' exceeds the limit of '%v' (0x%04X)", ErrOutOfRange, what, quantity, limit, limit)
+	}
+	return nil
+}
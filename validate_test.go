@@ -0,0 +1,134 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestValidateReadWriteMultipleRegisters(t *testing.T) {
+	tests := []struct {
+		name          string
+		readQuantity  uint16
+		writeQuantity uint16
+		value         []byte
+		want          error
+	}{
+		{"valid", 125, 121, make([]byte, 242), nil},
+		{"read quantity zero", 0, 1, make([]byte, 2), modbus.ErrReadQuantity},
+		{"read quantity too large", 126, 1, make([]byte, 2), modbus.ErrReadQuantity},
+		{"write quantity zero", 1, 0, nil, modbus.ErrWriteQuantity},
+		{"write quantity too large", 1, 122, make([]byte, 244), modbus.ErrWriteQuantity},
+		{"value length mismatch", 1, 2, make([]byte, 3), modbus.ErrWriteValueLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := modbus.ValidateReadWriteMultipleRegisters(tt.readQuantity, tt.writeQuantity, tt.value)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if !errors.Is(err, modbus.ErrOutOfRange) {
+				t.Fatalf("%v does not match ErrOutOfRange", err)
+			}
+		})
+	}
+}
+
+func TestValidateQuantityZeroAndOne(t *testing.T) {
+	codes := []byte{
+		modbus.READ_COILS,
+		modbus.READ_DISCRETE_INPUTS,
+		modbus.READ_HOLDING_REGISTERS,
+		modbus.READ_INPUT_REGISTERS,
+		modbus.WRITE_MULTIPLE_COILS,
+		modbus.WRITE_MULTIPLE_REGISTERS,
+	}
+	for _, code := range codes {
+		if err := modbus.ValidateQuantity(code, 0); !errors.Is(err, modbus.ErrOutOfRange) {
+			t.Errorf("function %v quantity 0: got %v, want ErrOutOfRange", code, err)
+		}
+		if err := modbus.ValidateQuantity(code, 1); err != nil {
+			t.Errorf("function %v quantity 1: unexpected error %v", code, err)
+		}
+	}
+}
+
+func TestZeroQuantityIsNotSent(t *testing.T) {
+	sent := false
+	s := &modbustest.MockSlaver{
+		ReadHoldingRegistersFunc: func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+			sent = true
+			return append([]byte{byte(quantity * 2)}, make([]byte, quantity*2)...), nil
+		},
+		WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+			sent = true
+			return []byte{byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}, nil
+		},
+	}
+	ctx := context.Background()
+	for _, quantity := range []uint16{0, 1} {
+		sent = false
+		_, err := modbus.ReadHoldingRegistersInto(ctx, s, 0, quantity, make([]byte, 2))
+		if quantity == 0 && (!errors.Is(err, modbus.ErrOutOfRange) || sent) {
+			t.Errorf("read quantity 0: err = %v, sent = %v", err, sent)
+		}
+		if quantity == 1 && (err != nil || !sent) {
+			t.Errorf("read quantity 1: err = %v, sent = %v", err, sent)
+		}
+
+		sent = false
+		_, err = modbus.WriteMultipleRegisters(ctx, s, 0, quantity, make([]byte, quantity*2))
+		if quantity == 0 && (!errors.Is(err, modbus.ErrOutOfRange) || sent) {
+			t.Errorf("write quantity 0: err = %v, sent = %v", err, sent)
+		}
+		if quantity == 1 && (err != nil || !sent) {
+			t.Errorf("write quantity 1: err = %v, sent = %v", err, sent)
+		}
+	}
+}
+
+func TestValidateQuantityLimits(t *testing.T) {
+	tests := []struct {
+		code     byte
+		quantity uint16
+		ok       bool
+	}{
+		{modbus.WRITE_MULTIPLE_COILS, 1968, true},
+		{modbus.WRITE_MULTIPLE_COILS, 1969, false},
+		{modbus.READ_COILS, 2000, true},
+		{modbus.READ_COILS, 2001, false},
+		{modbus.READ_DISCRETE_INPUTS, 2000, true},
+		{modbus.READ_DISCRETE_INPUTS, 2001, false},
+		{modbus.READ_HOLDING_REGISTERS, 125, true},
+		{modbus.READ_HOLDING_REGISTERS, 126, false},
+		{modbus.WRITE_MULTIPLE_REGISTERS, 123, true},
+		{modbus.WRITE_MULTIPLE_REGISTERS, 124, false},
+	}
+	for _, tt := range tests {
+		err := modbus.ValidateQuantity(tt.code, tt.quantity)
+		if tt.ok && err != nil {
+			t.Errorf("function %v quantity %v: unexpected error %v", tt.code, tt.quantity, err)
+		}
+		if !tt.ok && !errors.Is(err, modbus.ErrOutOfRange) {
+			t.Errorf("function %v quantity %v: got %v, want ErrOutOfRange", tt.code, tt.quantity, err)
+		}
+	}
+
+	// 错误信息需注明各自的上限
+	if err := modbus.ValidateQuantity(modbus.WRITE_MULTIPLE_COILS, 1969); err == nil || !strings.Contains(err.Error(), "'1968' (0x07B0)") {
+		t.Errorf("write coils limit not cited: %v", err)
+	}
+	if err := modbus.ValidateQuantity(modbus.READ_COILS, 2001); err == nil || !strings.Contains(err.Error(), "'2000' (0x07D0)") {
+		t.Errorf("read coils limit not cited: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 写入后回读的值与写入值不一致
+var ErrVerifyFailed = errors.New("modbus: verify failed")
+
+// 写入后回读校验的选项,零值表示写入后立即回读一次
+type VerifyOptions struct {
+	// 写入后(以及每次重新回读前)等待的时间,用于写入后不会立即反映到回读值的寄存器
+	SettleDelay time.Duration
+	// 回读值不一致时重新回读的次数,回读出错时不重试
+	Retries int
+}
+
+// WriteVerifyRegister 写单个保持寄存器后回读该寄存器,回读值与写入值不一致时返回ErrVerifyFailed
+// 适用于关键设定值;回读的等待与重试由opts配置
+func WriteVerifyRegister(ctx context.Context, s Slaver, address, value uint16, opts VerifyOptions) error {
+	if _, err := s.WriteSingleRegister(ctx, address, value); err != nil {
+		return err
+	}
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], value)
+	return opts.verify(ctx, s, address, 1, b[:])
+}
+
+// WriteVerifyRegisters 写多个保持寄存器后回读该寄存器块,回读值与写入值不一致时返回ErrVerifyFailed
+// value: quantity*2字节,数据;回读的等待与重试由opts配置
+func WriteVerifyRegisters(ctx context.Context, s Slaver, address, quantity uint16, value []byte, opts VerifyOptions) error {
+	if _, err := WriteMultipleRegisters(ctx, s, address, quantity, value); err != nil {
+		return err
+	}
+	return opts.verify(ctx, s, address, quantity, value)
+}
+
+// verify 按选项等待后回读寄存器块并与value比较,不一致时按Retries重新回读
+func (o VerifyOptions) verify(ctx context.Context, s Slaver, address, quantity uint16, value []byte) error {
+	for attempt := 0; ; attempt++ {
+		if o.SettleDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-clk.After(o.SettleDelay):
+			}
+		}
+		err := readBack(ctx, s, address, quantity, value)
+		if !errors.Is(err, ErrVerifyFailed) || attempt >= o.Retries {
+			return err
+		}
+	}
+}
+
+// readBack 回读寄存器块并与value比较
+func readBack(ctx context.Context, s Slaver, address, quantity uint16, value []byte) error {
+	results, err := s.ReadHoldingRegisters(ctx, address, quantity)
+	if err != nil {
+		return err
+	}
+	data, err := registerData(results, quantity)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data, value) {
+		for i := 0; i+1 < len(data) && i+1 < len(value); i += 2 {
+			if data[i] != value[i] || data[i+1] != value[i+1] {
+				return fmt.Errorf("%w: register '%v' reads back '%v', wrote '%v'", ErrVerifyFailed, int(address)+i/2, binary.BigEndian.Uint16(data[i:]), binary.BigEndian.Uint16(value[i:]))
+			}
+		}
+		return fmt.Errorf("%w: read back '%v' bytes, wrote '%v' bytes", ErrVerifyFailed, len(data), len(value))
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package modbus_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+// verifySlaver 模拟写入后经过lag次回读才反映新值的寄存器
+type verifySlaver struct {
+	modbustest.MockSlaver
+	registers map[uint16]uint16
+	pending   map[uint16]uint16
+	lag       int
+	reads     int
+}
+
+func newVerifySlaver(lag int) *verifySlaver {
+	s := &verifySlaver{registers: map[uint16]uint16{}, pending: map[uint16]uint16{}, lag: lag}
+	s.WriteSingleRegisterFunc = func(ctx context.Context, address, value uint16) ([]byte, error) {
+		s.pending[address] = value
+		return echo(address, value), nil
+	}
+	s.WriteMultipleregistersFunc = func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		for i := uint16(0); i < quantity; i++ {
+			s.pending[address+i] = binary.BigEndian.Uint16(value[i*2:])
+		}
+		return echo(address, quantity), nil
+	}
+	s.ReadHoldingRegistersFunc = func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		s.reads++
+		if s.reads > s.lag {
+			for a, v := range s.pending {
+				s.registers[a] = v
+			}
+		}
+		results := []byte{byte(quantity * 2)}
+		for i := uint16(0); i < quantity; i++ {
+			results = binary.BigEndian.AppendUint16(results, s.registers[address+i])
+		}
+		return results, nil
+	}
+	return s
+}
+
+func TestWriteVerifyRegister(t *testing.T) {
+	s := newVerifySlaver(0)
+	if err := modbus.WriteVerifyRegister(context.Background(), s, 7, 0x1234, modbus.VerifyOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.reads != 1 {
+		t.Fatalf("%v read-backs, want 1", s.reads)
+	}
+}
+
+func TestWriteVerifyRegisterMismatch(t *testing.T) {
+	s := newVerifySlaver(0)
+	s.WriteSingleRegisterFunc = func(ctx context.Context, address, value uint16) ([]byte, error) {
+		// 设备接受写入但忽略了该值
+		return echo(address, value), nil
+	}
+	err := modbus.WriteVerifyRegister(context.Background(), s, 7, 0x1234, modbus.VerifyOptions{})
+	if !errors.Is(err, modbus.ErrVerifyFailed) {
+		t.Fatalf("got %v, want ErrVerifyFailed", err)
+	}
+}
+
+func TestWriteVerifyRegistersSettleAndRetry(t *testing.T) {
+	clock := modbus.NewFakeClock(time.Unix(0, 0))
+	defer modbus.SetClock(clock)()
+	value := []byte{0x00, 0x01, 0x00, 0x02}
+
+	tests := []struct {
+		name    string
+		lag     int
+		retries int
+		want    error
+	}{
+		{"reflected on retry", 2, 2, nil},
+		{"retries exhausted", 3, 2, modbus.ErrVerifyFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newVerifySlaver(tt.lag)
+			done := make(chan error, 1)
+			go func() {
+				done <- modbus.WriteVerifyRegisters(context.Background(), s, 10, 2, value, modbus.VerifyOptions{SettleDelay: time.Second, Retries: tt.retries})
+			}()
+			// 每次回读前等待SettleDelay
+			for i := 0; i <= tt.retries; i++ {
+				clock.BlockUntil(1)
+				clock.Advance(time.Second)
+			}
+			if err := <-done; !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if want := tt.retries + 1; tt.want != nil && s.reads != want {
+				t.Fatalf("%v read-backs, want %v", s.reads, want)
+			}
+		})
+	}
+}
+
+func TestWriteVerifyRegistersReadError(t *testing.T) {
+	s := newVerifySlaver(0)
+	readErr := &modbus.Error{FunctionCode: modbus.READ_HOLDING_REGISTERS, ExceptionCode: modbus.ILLEGAL_DATA_ADDRESS}
+	s.ReadHoldingRegistersFunc = func(ctx context.Context, address, quantity uint16) ([]byte, error) {
+		s.reads++
+		return nil, readErr
+	}
+	err := modbus.WriteVerifyRegisters(context.Background(), s, 10, 1, []byte{0, 1}, modbus.VerifyOptions{Retries: 3})
+	if !errors.Is(err, readErr) || s.reads != 1 {
+		t.Fatalf("got %v after %v reads, want the read error without retry", err, s.reads)
+	}
+}
@@ -0,0 +1,53 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+)
+
+// WriteMultipleCoils 在远程设备中写多个线圈(0x0F),校验响应回显的地址和数量并返回设备确认写入的线圈数量
+// value的长度必须为ceil(quantity/8)字节且末字节未使用的高位为0,否则在发送请求前返回ErrOutOfRange
+func WriteMultipleCoils(ctx context.Context, s Slaver, address, quantity uint16, value []byte) (written uint16, err error) {
+	if err = ValidateQuantity(WRITE_MULTIPLE_COILS, quantity); err != nil {
+		return 0, err
+	}
+	if err = ValidateCoilValues(quantity, value); err != nil {
+		return 0, err
+	}
+	results, err := s.WriteMultipleCoils(ctx, address, quantity, value)
+	if err != nil {
+		return 0, err
+	}
+	return writeMultipleResponse(results, address, quantity)
+}
+
+// WriteMultipleRegisters 在远程设备中写多个寄存器(0x10),校验响应回显的地址和数量并返回设备确认写入的寄存器数量
+// value的长度必须为quantity*2字节,否则在发送请求前返回ErrOutOfRange
+func WriteMultipleRegisters(ctx context.Context, s Slaver, address, quantity uint16, value []byte) (written uint16, err error) {
+	if err = ValidateQuantity(WRITE_MULTIPLE_REGISTERS, quantity); err != nil {
+		return 0, err
+	}
+	if len(value) != int(quantity)*2 {
+		return 0, fmt.Errorf("%w: '%v' bytes of register values, quantity '%v' needs '%v' bytes", ErrOutOfRange, len(value), quantity, int(quantity)*2)
+	}
+	results, err := s.WriteMultipleregisters(ctx, address, quantity, value)
+	if err != nil {
+		return 0, err
+	}
+	return writeMultipleResponse(results, address, quantity)
+}
+
+// writeMultipleResponse 解析写多个线圈/寄存器的响应: 起始地址(2) + 数量(2)
+func writeMultipleResponse(results []byte, address, quantity uint16) (uint16, error) {
+	if len(results) != 4 {
+		return 0, fmt.Errorf("%w: '%v' bytes, expected '4' bytes", ErrInvalidResponse, len(results))
+	}
+	if a := binary.BigEndian.Uint16(results); a != address {
+		return 0, fmt.Errorf("%w: echoed address '%v' does not match '%v'", ErrInvalidResponse, a, address)
+	}
+	if q := binary.BigEndian.Uint16(results[2:]); q != quantity {
+		return q, fmt.Errorf("%w: echoed quantity '%v' does not match '%v'", ErrInvalidResponse, q, quantity)
+	}
+	return quantity, nil
+}
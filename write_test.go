@@ -0,0 +1,56 @@
+package modbus_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kokutas/modbus"
+	"github.com/kokutas/modbus/modbustest"
+)
+
+func TestWriteMultipleRegisters(t *testing.T) {
+	sent := false
+	s := &modbustest.MockSlaver{WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		sent = true
+		return []byte{byte(address >> 8), byte(address), byte(quantity >> 8), byte(quantity)}, nil
+	}}
+	tests := []struct {
+		name     string
+		quantity uint16
+		value    []byte
+		want     error
+	}{
+		{"valid", 2, []byte{0, 1, 0, 2}, nil},
+		{"short value", 2, []byte{1}, modbus.ErrOutOfRange},
+		{"long value", 1, []byte{0, 1, 0, 2}, modbus.ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = false
+			written, err := modbus.WriteMultipleRegisters(context.Background(), s, 0x10, tt.quantity, tt.value)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				if sent {
+					t.Fatal("malformed request was sent")
+				}
+				return
+			}
+			if written != tt.quantity {
+				t.Fatalf("written = %v, want %v", written, tt.quantity)
+			}
+		})
+	}
+}
+
+func TestWriteMultipleRegistersEchoMismatch(t *testing.T) {
+	s := &modbustest.MockSlaver{WriteMultipleregistersFunc: func(ctx context.Context, address, quantity uint16, value []byte) ([]byte, error) {
+		return []byte{0x00, 0x10, 0x00, 0x01}, nil
+	}}
+	written, err := modbus.WriteMultipleRegisters(context.Background(), s, 0x10, 2, []byte{0, 1, 0, 2})
+	if !errors.Is(err, modbus.ErrInvalidResponse) || written != 1 {
+		t.Fatalf("got (%v, %v), want (1, ErrInvalidResponse)", written, err)
+	}
+}